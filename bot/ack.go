@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AckPolicy configura el modo de procesamiento "al menos una vez".
+type AckPolicy struct {
+	// MaxAttempts es la cantidad de intentos por actualización, incluido el
+	// primero, antes de enviarla al DeadLetterSink.
+	MaxAttempts int
+	// RetryDelay es la espera entre intentos.
+	RetryDelay time.Duration
+}
+
+// DefaultAckPolicy retorna la política recomendada: 3 intentos con un
+// segundo de espera entre ellos.
+func DefaultAckPolicy() AckPolicy {
+	return AckPolicy{
+		MaxAttempts: 3,
+		RetryDelay:  time.Second,
+	}
+}
+
+// WithAckMode habilita el procesamiento "al menos una vez" en Start: una
+// actualización se confirma a Telegram y al OffsetStore solo cuando su
+// handler terminó sin error, y el offset avanza únicamente sobre las
+// actualizaciones confirmadas de forma contigua. Si el proceso se cae, las
+// actualizaciones sin confirmar se vuelven a recibir al reiniciar.
+//
+// Un handler falla si retorna error (los handlers On*, los CallbackHandler,
+// los CommandHandler registrados con Handle y los middlewares) o si entra en
+// pánico. Las actualizaciones que
+// fallan se reintentan según la política y, al agotar los intentos, se
+// envían al DeadLetterSink configurado con WithDeadLetterSink.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token,
+//	    bot.WithAckMode(bot.DefaultAckPolicy()),
+//	    bot.WithOffsetStore(bot.NewFileOffsetStore("data/offset")),
+//	)
+func WithAckMode(policy AckPolicy) BotOption {
+	return func(b *Bot) {
+		b.ackPolicy = &policy
+	}
+}
+
+// processUpdate procesa una actualización recibida por Start e indica si
+// quedó confirmada. Sin modo ack todas las actualizaciones se confirman; si
+// hay un DeadLetterSink, las que fallan o entran en pánico en su único
+// intento se envían al sink.
+func (b *Bot) processUpdate(ctx context.Context, update *Update) bool {
+	attempts := 1
+	if b.ackPolicy != nil {
+		attempts = max(b.ackPolicy.MaxAttempts, 1)
+	} else if b.deadLetters == nil {
+		b.handleUpdate(ctx, update)
+		return true
+	}
+
+	handler := Recover()(func(ctx context.Context, b *Bot, update *Update) error {
+		return b.runUpdate(ctx, update)
+	})
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = handler(ctx, b, update); err == nil {
+			return true
+		}
+
+		// Durante el shutdown no se reintenta: la actualización queda sin
+		// confirmar y se vuelve a recibir al reiniciar
+		if b.ackPolicy != nil && ctx.Err() != nil {
+			return false
+		}
+
+		if attempt < attempts {
+			b.logger.Warn("Reintentando update",
+				append(updateAttrs(update),
+					slog.Int("attempt", attempt),
+					slog.String("error", err.Error()),
+				)...,
+			)
+			if b.clock.Sleep(ctx, b.ackPolicy.RetryDelay) != nil {
+				return false
+			}
+		}
+	}
+
+	letter := DeadLetter{
+		Update:   update,
+		Raw:      update.rawJSON(),
+		Error:    err.Error(),
+		Handler:  b.handlerName(update),
+		Attempts: attempts,
+	}
+	return b.putDeadLetter(ctx, letter) || b.ackPolicy == nil
+}
+
+// pollOffset retorna el offset para getUpdates y el OffsetStore. En modo ack
+// no pasa de la primera actualización sin confirmar, para que Telegram la
+// vuelva a entregar si el proceso se cae.
+func (b *Bot) pollOffset(d *dispatcher) int {
+	if b.ackPolicy != nil {
+		if first, ok := d.firstUnfinished(); ok {
+			return first
+		}
+	}
+	return b.offset
+}
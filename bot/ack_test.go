@@ -0,0 +1,449 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryDeadLetterSink guarda las dead letters en memoria. Las primeras
+// failures llamadas a Put fallan.
+type memoryDeadLetterSink struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	letters  []DeadLetter
+}
+
+func (s *memoryDeadLetterSink) Put(ctx context.Context, letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// failTimes retorna un middleware que hace fallar las primeras n ejecuciones
+// con err y cuenta todas en calls.
+func failTimes(n int, err error, calls *int) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, b *Bot, update *Update) error {
+			*calls++
+			if *calls <= n {
+				return err
+			}
+			return next(ctx, b, update)
+		}
+	}
+}
+
+func messageUpdate(id int) *Update {
+	return &Update{UpdateID: id, Message: &Message{
+		MessageID: id,
+		Chat:      &Chat{ID: 1},
+		From:      &User{ID: 1, FirstName: "A"},
+		Text:      "hola",
+	}}
+}
+
+func TestBot_processUpdate_AckMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		panics       bool
+		sinkFailures int
+		wantCalls    int
+		wantSleeps   int
+		wantError    string // Error de la dead letter; vacío si no debe haber una
+	}{
+		{name: "succeeds first time", wantCalls: 1},
+		{name: "succeeds after retry", failures: 2, wantCalls: 3, wantSleeps: 2},
+		{name: "exhausts attempts", failures: 5, wantCalls: 3, wantSleeps: 2, wantError: "boom"},
+		{name: "panic counts as failure", panics: true, wantCalls: 3, wantSleeps: 2, wantError: "handler panic: boom"},
+		{name: "retries failing sink", failures: 5, sinkFailures: 2, wantCalls: 3, wantSleeps: 4, wantError: "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			middleware := failTimes(tt.failures, errors.New("boom"), &calls)
+			if tt.panics {
+				middleware = func(next Handler) Handler {
+					return func(ctx context.Context, b *Bot, update *Update) error {
+						calls++
+						panic("boom")
+					}
+				}
+			}
+
+			sink := &memoryDeadLetterSink{failures: tt.sinkFailures}
+			clock := newFakeClock()
+			bot := NewBot("test-token",
+				WithLogger(testLogger()),
+				WithAckMode(AckPolicy{MaxAttempts: 3, RetryDelay: time.Second}),
+				WithDeadLetterSink(sink),
+				WithMiddleware(middleware),
+			)
+			bot.clock = clock
+
+			update := messageUpdate(7)
+			if !bot.processUpdate(context.Background(), update) {
+				t.Fatal("expected update to be acknowledged")
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d attempts, got %d", tt.wantCalls, calls)
+			}
+			if got := len(clock.Sleeps()); got != tt.wantSleeps {
+				t.Errorf("expected %d retry delays, got %d", tt.wantSleeps, got)
+			}
+
+			if tt.wantError == "" {
+				if len(sink.letters) != 0 {
+					t.Errorf("expected no dead letters, got %+v", sink.letters)
+				}
+				return
+			}
+			if len(sink.letters) != 1 {
+				t.Fatalf("expected 1 dead letter, got %d", len(sink.letters))
+			}
+			letter := sink.letters[0]
+			if letter.Update != update || letter.Error != tt.wantError || letter.Attempts != 3 {
+				t.Errorf("unexpected dead letter: %+v", letter)
+			}
+		})
+	}
+}
+
+func TestBot_putDeadLetter_Backoff(t *testing.T) {
+	// Sin RetryDelay, los reintentos del sink igual esperan, y cada vez más
+	sink := &memoryDeadLetterSink{failures: 8}
+	clock := newFakeClock()
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithAckMode(AckPolicy{MaxAttempts: 1, RetryDelay: 0}),
+		WithDeadLetterSink(sink),
+		WithMiddleware(failTimes(1, errors.New("boom"), new(int))),
+	)
+	bot.clock = clock
+
+	if !bot.processUpdate(context.Background(), messageUpdate(1)) {
+		t.Fatal("expected update to be acknowledged after reaching the sink")
+	}
+
+	want := []time.Duration{
+		500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second,
+		8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second,
+	}
+	if got := clock.Sleeps(); !slices.Equal(got, want) {
+		t.Errorf("expected sink retry delays %v, got %v", want, got)
+	}
+	if len(sink.letters) != 1 {
+		t.Errorf("expected the dead letter to be stored, got %d", len(sink.letters))
+	}
+}
+
+func TestBot_processUpdate_AckModeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithAckMode(DefaultAckPolicy()),
+		WithMiddleware(func(next Handler) Handler {
+			return func(ctx context.Context, b *Bot, update *Update) error {
+				cancel()
+				return ctx.Err()
+			}
+		}),
+	)
+
+	// Un update interrumpido por el shutdown no se confirma ni se reintenta
+	if bot.processUpdate(ctx, messageUpdate(1)) {
+		t.Error("expected canceled update not to be acknowledged")
+	}
+}
+
+func TestBot_processUpdate_WithoutAckMode(t *testing.T) {
+	var calls int
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithMiddleware(failTimes(1, errors.New("boom"), &calls)),
+	)
+
+	if !bot.processUpdate(context.Background(), messageUpdate(1)) {
+		t.Error("expected update to be acknowledged without ack mode")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt without ack mode, got %d", calls)
+	}
+}
+
+func TestCommandRegistry_Handle(t *testing.T) {
+	registry := NewCommandRegistry()
+	registry.Handle("pay", func(ctx context.Context, b *Bot, msg *Message) error {
+		return errors.New("payment failed")
+	})
+
+	bot := NewBot("test-token", WithLogger(testLogger()), WithCommandRegistry(registry))
+	update := &Update{UpdateID: 1, Message: &Message{
+		Chat:     &Chat{ID: 1},
+		From:     &User{ID: 1, FirstName: "A"},
+		Text:     "/pay",
+		Entities: []MessageEntity{{Type: "bot_command", Offset: 0, Length: 4}},
+	}}
+
+	if err := bot.runUpdate(context.Background(), update); err == nil || err.Error() != "payment failed" {
+		t.Errorf("expected handler error to be returned, got %v", err)
+	}
+}
+
+func TestBot_processUpdate_HandlerErrors(t *testing.T) {
+	// Los errores de los handlers On* y de las rutas de callbacks cuentan
+	// como fallas igual que los de los comandos
+	fail := errors.New("handler failed")
+	callback := &Update{UpdateID: 3, CallbackQuery: &CallbackQuery{ID: "1", From: &User{ID: 1}, Data: "confirm:yes"}}
+
+	tests := []struct {
+		name   string
+		update *Update
+		opts   func(calls *int) []BotOption
+	}{
+		{
+			name:   "OnMessage",
+			update: messageUpdate(1),
+			opts: func(calls *int) []BotOption {
+				return []BotOption{OnMessage(func(ctx context.Context, b *Bot, msg *Message) error { *calls++; return fail })}
+			},
+		},
+		{
+			name:   "OnPoll",
+			update: &Update{UpdateID: 2, Poll: &Poll{ID: "1", Question: "¿?"}},
+			opts: func(calls *int) []BotOption {
+				return []BotOption{OnPoll(func(ctx context.Context, b *Bot, p *Poll) error { *calls++; return fail })}
+			},
+		},
+		{
+			name:   "OnCallbackQuery",
+			update: callback,
+			opts: func(calls *int) []BotOption {
+				return []BotOption{OnCallbackQuery(func(ctx context.Context, b *Bot, q *CallbackQuery) error { *calls++; return fail })}
+			},
+		},
+		{
+			name:   "callback registry route",
+			update: callback,
+			opts: func(calls *int) []BotOption {
+				registry := NewCallbackRegistry()
+				registry.RegisterPrefix("confirm:", func(ctx context.Context, b *Bot, q *CallbackQuery) error { *calls++; return fail })
+				return []BotOption{WithCallbackRegistry(registry)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			sink := &memoryDeadLetterSink{}
+			opts := append([]BotOption{
+				WithLogger(testLogger()),
+				WithAckMode(AckPolicy{MaxAttempts: 2}),
+				WithDeadLetterSink(sink),
+			}, tt.opts(&calls)...)
+			bot := NewBot("test-token", opts...)
+			bot.clock = newFakeClock()
+
+			if !bot.processUpdate(context.Background(), tt.update) {
+				t.Fatal("expected update to be acknowledged after reaching the sink")
+			}
+
+			if calls != 2 {
+				t.Errorf("expected the handler to be retried, got %d calls", calls)
+			}
+			if len(sink.letters) != 1 || sink.letters[0].Error != fail.Error() {
+				t.Errorf("expected the handler error in a dead letter, got %+v", sink.letters)
+			}
+		})
+	}
+}
+
+func TestBot_Start_AckModeOffset(t *testing.T) {
+	// El update 1 queda en proceso y el 2, de otro chat, termina: el offset
+	// no puede pasar del 1
+	fake := &pollingServer{batch: `{"update_id":1,"message":{"message_id":1,"chat":{"id":1},"from":{"id":1,"first_name":"A"},"text":"lento"}},` +
+		`{"update_id":2,"message":{"message_id":2,"chat":{"id":2},"from":{"id":2,"first_name":"B"},"text":"rápido"}}`}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := NewMemoryOffsetStore()
+	handled := make(chan string, 2)
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithAckMode(DefaultAckPolicy()),
+		WithOffsetStore(store),
+		WithShutdownGracePeriod(0),
+		OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+			if msg.Text == "lento" {
+				<-ctx.Done()
+				return nil
+			}
+			handled <- msg.Text
+			return nil
+		}),
+	)
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Start(ctx) }()
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("update 2 was not handled")
+	}
+
+	// Esperar el long polling siguiente al lote
+	deadline := time.Now().Add(time.Second)
+	for {
+		fake.mu.Lock()
+		polls := append([]int(nil), fake.polls...)
+		fake.mu.Unlock()
+		if len(polls) >= 2 {
+			if polls[1] != 1 {
+				t.Errorf("expected polling to resume from unacknowledged update 1, got offset %d", polls[1])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("polling did not resume after the batch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	if offset, _ := store.Load(context.Background()); offset != 1 {
+		t.Errorf("expected stored offset 1, got %d", offset)
+	}
+	if confirmed, _ := fake.snapshot(); len(confirmed) != 1 || confirmed[0] != 1 {
+		t.Errorf("expected final offset 1 to be confirmed, got %v", confirmed)
+	}
+}
+
+func TestBot_Start_AckModeRedelivery(t *testing.T) {
+	// Telegram vuelve a entregar los updates sin confirmar: los que ya están
+	// en proceso no se despachan dos veces
+	fake := &redeliveryServer{batch: shutdownBatch}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var texts []string
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithAckMode(DefaultAckPolicy()),
+		OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+			<-release
+			mu.Lock()
+			texts = append(texts, msg.Text)
+			mu.Unlock()
+			return nil
+		}),
+	)
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Start(ctx) }()
+
+	// Con los updates en proceso, el polling espera en lugar de repetir el lote
+	time.Sleep(50 * time.Millisecond)
+	if polls := fake.count(); polls > 2 {
+		t.Errorf("expected polling to wait for in-flight updates, got %d polls", polls)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for fake.lastOffset() != 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	if got := strings.Join(texts, ","); got != "uno,dos" {
+		t.Errorf("expected each update to be handled once, got %s", got)
+	}
+	if offset := fake.lastOffset(); offset != 3 {
+		t.Errorf("expected polling to advance to offset 3, got %d", offset)
+	}
+}
+
+// redeliveryServer simula getUpdates como Telegram: entrega los updates del
+// lote con ID mayor o igual al offset pedido y mantiene abierto el long
+// polling si no hay ninguno.
+type redeliveryServer struct {
+	batch string
+
+	mu    sync.Mutex
+	polls []int
+}
+
+func (s *redeliveryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/getMe") {
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Bot","username":"testbot"}}`))
+		return
+	}
+
+	var params struct {
+		Offset  int `json:"offset"`
+		Timeout int `json:"timeout"`
+	}
+	json.NewDecoder(r.Body).Decode(&params)
+
+	var updates []Update
+	json.Unmarshal([]byte("["+s.batch+"]"), &updates)
+	pending := slices.DeleteFunc(updates, func(u Update) bool { return u.UpdateID < params.Offset })
+
+	if params.Timeout > 0 {
+		s.mu.Lock()
+		s.polls = append(s.polls, params.Offset)
+		s.mu.Unlock()
+
+		if len(pending) == 0 {
+			<-r.Context().Done()
+			return
+		}
+	}
+
+	result, _ := json.Marshal(pending)
+	w.Write([]byte(`{"ok":true,"result":` + string(result) + `}`))
+}
+
+func (s *redeliveryServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.polls)
+}
+
+func (s *redeliveryServer) lastOffset() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.polls) == 0 {
+		return 0
+	}
+	return s.polls[len(s.polls)-1]
+}
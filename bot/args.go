@@ -0,0 +1,358 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ArgKind es el tipo al que se convierte un argumento de comando.
+type ArgKind int
+
+const (
+	ArgString   ArgKind = iota // Una palabra, o un texto entre comillas
+	ArgInt                     // Número entero
+	ArgDuration                // Duración como 30s, 10m, 2h o 1d
+	ArgRest                    // El resto de la línea, sin interpretar
+)
+
+// ArgSpec declara un argumento de un comando. Se construye con StringArg,
+// IntArg, DurationArg o RestArg.
+type ArgSpec struct {
+	name     string
+	kind     ArgKind
+	optional bool
+	named    bool
+}
+
+// StringArg declara un argumento de texto. Los textos con espacios se
+// escriben entre comillas: /mute @user 10m "spam en el grupo".
+func StringArg(name string) ArgSpec {
+	return ArgSpec{name: name, kind: ArgString}
+}
+
+// IntArg declara un argumento numérico entero.
+func IntArg(name string) ArgSpec {
+	return ArgSpec{name: name, kind: ArgInt}
+}
+
+// DurationArg declara un argumento de duración. Acepta el formato de
+// time.ParseDuration y además días, como "1d".
+func DurationArg(name string) ArgSpec {
+	return ArgSpec{name: name, kind: ArgDuration}
+}
+
+// RestArg declara un argumento que toma el resto de la línea tal como fue
+// escrito. Debe ser el último argumento posicional.
+func RestArg(name string) ArgSpec {
+	return ArgSpec{name: name, kind: ArgRest}
+}
+
+// Optional marca el argumento como opcional. Los argumentos posicionales
+// opcionales deben estar después de los obligatorios.
+func (a ArgSpec) Optional() ArgSpec {
+	a.optional = true
+	return a
+}
+
+// Named hace que el argumento se indique como nombre=valor, en cualquier
+// posición: /ban @user reason="flood".
+func (a ArgSpec) Named() ArgSpec {
+	a.named = true
+	return a
+}
+
+// Args contiene los argumentos de un comando ya convertidos a su tipo.
+// Los métodos retornan el valor cero si el argumento no fue indicado.
+type Args struct {
+	values map[string]any
+}
+
+type argsContextKey struct{}
+
+// ArgsFromContext retorna los argumentos interpretados del comando en
+// ejecución, o nil si el comando no declaró argumentos con WithArgs.
+//
+// Ejemplo:
+//
+//	func commandMute(ctx context.Context, b *bot.Bot, msg *bot.Message) {
+//	    args := bot.ArgsFromContext(ctx)
+//	    user, duration := args.String("user"), args.Duration("duration")
+//	    // ...
+//	}
+func ArgsFromContext(ctx context.Context) *Args {
+	args, _ := ctx.Value(argsContextKey{}).(*Args)
+	return args
+}
+
+// Has indica si el argumento fue indicado.
+func (a *Args) Has(name string) bool {
+	if a == nil {
+		return false
+	}
+	_, ok := a.values[name]
+	return ok
+}
+
+// String retorna un argumento de tipo ArgString o ArgRest.
+func (a *Args) String(name string) string {
+	if a == nil {
+		return ""
+	}
+	s, _ := a.values[name].(string)
+	return s
+}
+
+// Int retorna un argumento de tipo ArgInt.
+func (a *Args) Int(name string) int {
+	if a == nil {
+		return 0
+	}
+	n, _ := a.values[name].(int)
+	return n
+}
+
+// Duration retorna un argumento de tipo ArgDuration.
+func (a *Args) Duration(name string) time.Duration {
+	if a == nil {
+		return 0
+	}
+	d, _ := a.values[name].(time.Duration)
+	return d
+}
+
+// argSchema es la lista de argumentos declarada para un comando.
+type argSchema []ArgSpec
+
+// validate verifica que el esquema sea coherente. Los errores son de
+// programación, por lo que Register los reporta con panic.
+func (s argSchema) validate() error {
+	seen := make(map[string]bool)
+	optional := false
+	rest := false
+
+	for _, spec := range s {
+		if spec.name == "" {
+			return errors.New("argument name cannot be empty")
+		}
+		if seen[spec.name] {
+			return fmt.Errorf("duplicate argument %q", spec.name)
+		}
+		seen[spec.name] = true
+
+		if spec.named {
+			if spec.kind == ArgRest {
+				return fmt.Errorf("rest argument %q cannot be named", spec.name)
+			}
+			continue
+		}
+
+		if rest {
+			return fmt.Errorf("argument %q declared after a rest argument", spec.name)
+		}
+		if optional && !spec.optional {
+			return fmt.Errorf("required argument %q declared after an optional one", spec.name)
+		}
+		optional = optional || spec.optional
+		rest = spec.kind == ArgRest
+	}
+
+	return nil
+}
+
+// usage arma la línea de uso del comando, por ejemplo
+// "/mute <user> <duration> [reason...]".
+func (s argSchema) usage(command string) string {
+	parts := []string{"/" + command}
+	for _, spec := range s {
+		var part string
+		switch {
+		case spec.named:
+			part = spec.name + "=..."
+		case spec.kind == ArgRest:
+			part = spec.name + "..."
+		default:
+			part = spec.name
+		}
+
+		if spec.optional {
+			part = "[" + part + "]"
+		} else if !spec.named {
+			part = "<" + part + ">"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// parse interpreta el texto que sigue al comando según el esquema. Los
+// errores están en español porque se muestran al usuario.
+func (s argSchema) parse(text string) (*Args, error) {
+	named := make(map[string]ArgSpec)
+	var positional []ArgSpec
+	for _, spec := range s {
+		if spec.named {
+			named[spec.name] = spec
+		} else {
+			positional = append(positional, spec)
+		}
+	}
+
+	args := &Args{values: make(map[string]any)}
+	next := 0
+	for pos := skipSpaces(text, 0); pos < len(text); pos = skipSpaces(text, pos) {
+		tok, end, err := scanArg(text, pos)
+
+		// Un argumento entre comillas nunca se interpreta como name=value
+		if err == nil && !tok.quoted {
+			if name, value, ok := strings.Cut(tok.value, "="); ok {
+				if spec, found := named[name]; found {
+					if err := args.set(spec, value); err != nil {
+						return nil, err
+					}
+					pos = end
+					continue
+				}
+			}
+		}
+
+		if next >= len(positional) {
+			if err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("argumento de más: %q", tok.value)
+		}
+
+		spec := positional[next]
+		next++
+
+		// El resto de la línea se toma sin interpretar, aunque tenga comillas
+		// sin cerrar
+		if spec.kind == ArgRest {
+			args.values[spec.name] = strings.TrimSpace(text[pos:])
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		if err := args.set(spec, tok.value); err != nil {
+			return nil, err
+		}
+		pos = end
+	}
+
+	for _, spec := range s {
+		if !spec.optional && !args.Has(spec.name) {
+			return nil, fmt.Errorf("falta el argumento %s", spec.name)
+		}
+	}
+
+	return args, nil
+}
+
+func (a *Args) set(spec ArgSpec, value string) error {
+	switch spec.kind {
+	case ArgInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s debe ser un número entero, se recibió %q", spec.name, value)
+		}
+		a.values[spec.name] = n
+	case ArgDuration:
+		d, err := parseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s debe ser una duración como 30s, 10m, 2h o 1d, se recibió %q", spec.name, value)
+		}
+		a.values[spec.name] = d
+	default:
+		a.values[spec.name] = value
+	}
+	return nil
+}
+
+// parseDuration extiende time.ParseDuration con días ("1d", "7d").
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// argToken es una palabra de los argumentos, con las comillas ya removidas.
+// quoted indica si empieza con comillas.
+type argToken struct {
+	value  string
+	quoted bool
+}
+
+// skipSpaces retorna la posición del primer carácter que no es un espacio a
+// partir de pos.
+func skipSpaces(text string, pos int) int {
+	return len(text) - len(strings.TrimLeftFunc(text[pos:], unicode.IsSpace))
+}
+
+// scanArg lee la palabra que comienza en start y retorna la posición donde
+// termina. Las comillas dobles agrupan textos con espacios y admiten \" para
+// incluir comillas; también se aceptan las comillas tipográficas “…” que
+// insertan algunos teclados. Las comillas simples no agrupan, para no romper
+// textos como "don't".
+func scanArg(text string, start int) (argToken, int, error) {
+	var (
+		tok     argToken
+		current strings.Builder
+		closing rune
+		escaped bool
+	)
+
+	for i, r := range text[start:] {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case closing != 0:
+			switch {
+			case r == '\\' && closing == '"':
+				escaped = true
+			case r == closing:
+				closing = 0
+			default:
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '“':
+			tok.quoted = tok.quoted || i == 0
+			closing = '"'
+			if r == '“' {
+				closing = '”'
+			}
+		case unicode.IsSpace(r):
+			tok.value = current.String()
+			return tok, start + i, nil
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if closing != 0 {
+		return argToken{}, len(text), errors.New("faltan comillas de cierre")
+	}
+
+	tok.value = current.String()
+	return tok, len(text), nil
+}
@@ -0,0 +1,291 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanArg(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		want       []string
+		wantQuoted []bool
+		wantErr    bool
+	}{
+		{name: "words", text: "@user 10m", want: []string{"@user", "10m"}},
+		{name: "extra spaces", text: "  a   b\nc ", want: []string{"a", "b", "c"}},
+		{name: "quoted", text: `@user "reason here" x`, want: []string{"@user", "reason here", "x"}, wantQuoted: []bool{false, true, false}},
+		{name: "escaped quote", text: `"dijo \"hola\""`, want: []string{`dijo "hola"`}, wantQuoted: []bool{true}},
+		{name: "typographic quotes", text: "“reason here”", want: []string{"reason here"}, wantQuoted: []bool{true}},
+		{name: "named quoted", text: `reason="a b"`, want: []string{"reason=a b"}, wantQuoted: []bool{false}},
+		{name: "quoted named", text: `"reason=a b"`, want: []string{"reason=a b"}, wantQuoted: []bool{true}},
+		{name: "apostrophe", text: "don't stop", want: []string{"don't", "stop"}},
+		{name: "empty quoted", text: `""`, want: []string{""}, wantQuoted: []bool{true}},
+		{name: "empty", text: "", want: nil},
+		{name: "unclosed quote", text: `"reason`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var quoted []bool
+			for pos := skipSpaces(tt.text, 0); pos < len(tt.text); pos = skipSpaces(tt.text, pos) {
+				tok, end, err := scanArg(tt.text, pos)
+				if err != nil {
+					if !tt.wantErr {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					return
+				}
+				got = append(got, tok.value)
+				quoted = append(quoted, tok.quoted)
+				pos = end
+			}
+			if tt.wantErr {
+				t.Fatal("expected error, got nil")
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if tt.wantQuoted != nil && !reflect.DeepEqual(quoted, tt.wantQuoted) {
+				t.Errorf("expected quoted %v, got %v", tt.wantQuoted, quoted)
+			}
+		})
+	}
+}
+
+func TestArgSchema_parse(t *testing.T) {
+	mute := argSchema{
+		StringArg("user"),
+		DurationArg("duration"),
+		StringArg("reason").Optional(),
+		IntArg("warn").Named().Optional(),
+	}
+	note := argSchema{
+		IntArg("priority"),
+		RestArg("text"),
+	}
+
+	tests := []struct {
+		name    string
+		schema  argSchema
+		text    string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name:   "all positional",
+			schema: mute,
+			text:   `@user 10m "reason here"`,
+			want:   map[string]any{"user": "@user", "duration": 10 * time.Minute, "reason": "reason here"},
+		},
+		{
+			name:   "optional omitted",
+			schema: mute,
+			text:   "@user 1d",
+			want:   map[string]any{"user": "@user", "duration": 24 * time.Hour},
+		},
+		{
+			name:   "named in any position",
+			schema: mute,
+			text:   "warn=2 @user 1h30m",
+			want:   map[string]any{"user": "@user", "duration": 90 * time.Minute, "warn": 2},
+		},
+		{
+			name:   "rest of line keeps original text",
+			schema: note,
+			text:   `3 comprar "pan"  y leche`,
+			want:   map[string]any{"priority": 3, "text": `comprar "pan"  y leche`},
+		},
+		{
+			name:   "rest of line with unclosed quote",
+			schema: note,
+			text:   `5 he said "hi`,
+			want:   map[string]any{"priority": 5, "text": `he said "hi`},
+		},
+		{
+			name:   "quoted text is not a named argument",
+			schema: mute,
+			text:   `@user 10m "warn=2"`,
+			want:   map[string]any{"user": "@user", "duration": 10 * time.Minute, "reason": "warn=2"},
+		},
+		{
+			name:    "missing required",
+			schema:  mute,
+			text:    "@user",
+			wantErr: "falta el argumento duration",
+		},
+		{
+			name:    "invalid duration",
+			schema:  mute,
+			text:    "@user mañana",
+			wantErr: "duration debe ser una duración",
+		},
+		{
+			name:    "invalid int",
+			schema:  note,
+			text:    "alta comprar pan",
+			wantErr: "priority debe ser un número entero",
+		},
+		{
+			name:    "too many arguments",
+			schema:  mute,
+			text:    "@user 10m reason extra",
+			wantErr: `argumento de más: "extra"`,
+		},
+		{
+			name:    "unclosed quote",
+			schema:  mute,
+			text:    `@user 10m "reason`,
+			wantErr: "faltan comillas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := tt.schema.parse(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(args.values, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, args.values)
+			}
+		})
+	}
+}
+
+func TestArgSchema_usage(t *testing.T) {
+	schema := argSchema{
+		StringArg("user"),
+		DurationArg("duration"),
+		RestArg("reason").Optional(),
+		IntArg("warn").Named().Optional(),
+		StringArg("scope").Named(),
+	}
+
+	want := "/mute <user> <duration> [reason...] [warn=...] scope=..."
+	if got := schema.usage("mute"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCommandRegistry_Register_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []ArgSpec
+	}{
+		{name: "required after optional", args: []ArgSpec{StringArg("a").Optional(), StringArg("b")}},
+		{name: "argument after rest", args: []ArgSpec{RestArg("a"), StringArg("b")}},
+		{name: "duplicate name", args: []ArgSpec{StringArg("a"), IntArg("a")}},
+		{name: "named rest", args: []ArgSpec{RestArg("a").Named()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected Register to panic")
+				}
+			}()
+
+			NewCommandRegistry().Register("cmd", func(context.Context, *Bot, *Message) {}, WithArgs(tt.args...))
+		})
+	}
+}
+
+func TestArgs_NilSafe(t *testing.T) {
+	args := ArgsFromContext(context.Background())
+
+	if args != nil || args.Has("x") || args.String("x") != "" || args.Int("x") != 0 || args.Duration("x") != 0 {
+		t.Error("expected nil Args to return zero values")
+	}
+}
+
+func TestCommandRegistry_Execute_Args(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantCalled bool
+		wantReply  string
+	}{
+		{
+			name:       "valid arguments",
+			text:       `/mute @user 10m "spam en el grupo"`,
+			wantCalled: true,
+		},
+		{
+			name:      "invalid arguments reply usage",
+			text:      "/mute @user",
+			wantReply: "falta el argumento duration\nUso: /mute <user> <duration> [reason]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reply SendMessageRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&reply)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"ok":true,"result":{"message_id":2}}`))
+			}))
+			defer server.Close()
+
+			var got *Args
+			registry := NewCommandRegistry()
+			registry.Register("mute", func(ctx context.Context, b *Bot, msg *Message) {
+				got = ArgsFromContext(ctx)
+			}, WithArgs(
+				StringArg("user"),
+				DurationArg("duration"),
+				StringArg("reason").Optional(),
+			))
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			msg := commandMessage(tt.text)
+			msg.MessageID = 10
+			if !registry.Execute(context.Background(), bot, msg) {
+				t.Fatal("expected command to be handled")
+			}
+
+			if (got != nil) != tt.wantCalled {
+				t.Fatalf("expected handler called=%v, got %v", tt.wantCalled, got != nil)
+			}
+
+			if tt.wantCalled {
+				if got.String("user") != "@user" || got.Duration("duration") != 10*time.Minute || got.String("reason") != "spam en el grupo" {
+					t.Errorf("unexpected args %v", got.values)
+				}
+				return
+			}
+
+			if reply.Text != tt.wantReply {
+				t.Errorf("expected reply %q, got %q", tt.wantReply, reply.Text)
+			}
+
+			if reply.ReplyParameters == nil || reply.ReplyParameters.MessageID != 10 {
+				t.Errorf("expected usage reply to the command message, got %+v", reply.ReplyParameters)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/totote05/go-toolkit/pkg/logger"
@@ -21,12 +22,28 @@ const (
 )
 
 type Bot struct {
-	token           string
-	client          *http.Client
-	offset          int
-	commandRegistry *CommandRegistry
-	apiBaseURL      string // Para testing, por defecto usa la constante apiURL
-	logger          *slog.Logger
+	token            string
+	client           *http.Client
+	offset           int
+	commandRegistry  *CommandRegistry
+	callbackRegistry *CallbackRegistry
+	apiBaseURL       string // Para testing, por defecto usa la constante apiURL
+	logger           *slog.Logger
+	webhookSecret    string
+	retryPolicy      *RetryPolicy
+	limiter          *rateLimiter
+	clock            clock
+	handlers         updateHandlers
+	allowedUpdates   []string
+	maxDownloadSize  int64
+	me               atomic.Pointer[User]
+	mentionFold      bool
+	middlewares      []Middleware
+	dispatch         DispatchConfig
+	gracePeriod      time.Duration
+	offsetStore      OffsetStore
+	ackPolicy        *AckPolicy
+	deadLetters      DeadLetterSink
 }
 
 // BotOption es una función que configura opciones del Bot.
@@ -60,6 +77,14 @@ func WithCommandRegistry(registry *CommandRegistry) BotOption {
 	}
 }
 
+// WithCaseInsensitiveMentions hace que la mención del bot en los comandos
+// ("/start@MiBot") se compare con su username sin distinguir mayúsculas.
+func WithCaseInsensitiveMentions() BotOption {
+	return func(b *Bot) {
+		b.mentionFold = true
+	}
+}
+
 // defaultLogger crea un logger por defecto usando el handler de go-toolkit.
 func defaultLogger() *slog.Logger {
 	handler := logger.NewHandler(os.Stdout, &logger.HandlerOptions{
@@ -93,9 +118,14 @@ func NewBot(token string, opts ...BotOption) *Bot {
 		client: &http.Client{
 			Timeout: time.Second * 70, // un poco más que el timeout de long polling
 		},
-		offset:     0,
-		apiBaseURL: apiURL,          // Usar la constante por defecto
-		logger:     defaultLogger(), // Logger por defecto
+		offset:          0,
+		apiBaseURL:      apiURL,          // Usar la constante por defecto
+		logger:          defaultLogger(), // Logger por defecto
+		clock:           realClock{},
+		maxDownloadSize: DefaultMaxDownloadSize,
+		dispatch:        DefaultDispatchConfig(),
+		gracePeriod:     DefaultShutdownGracePeriod,
+		offsetStore:     NewMemoryOffsetStore(),
 	}
 
 	// Aplicar opciones
@@ -107,9 +137,11 @@ func NewBot(token string, opts ...BotOption) *Bot {
 }
 
 func (b *Bot) makeRequest(ctx context.Context, method string, payload any) (*Response, error) {
-	url := fmt.Sprintf(b.apiBaseURL, b.token, method)
+	if files := pendingUploads(payload); len(files) > 0 {
+		return b.uploadRequest(ctx, method, payload, files)
+	}
 
-	var body io.Reader
+	var body []byte
 	if payload != nil {
 		jsonData, err := json.Marshal(payload)
 		if err != nil {
@@ -119,8 +151,45 @@ func (b *Bot) makeRequest(ctx context.Context, method string, payload any) (*Res
 			)
 			return nil, fmt.Errorf("error marshaling payload: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
+		body = jsonData
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := b.waitRateLimit(ctx, payload); err != nil {
+			return nil, err
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+
+		resp, err := b.doRequest(ctx, method, reader, "application/json")
+		if err == nil {
+			return resp, nil
+		}
+
+		delay, retry := b.retryPolicy.shouldRetry(method, attempt, err)
+		if !retry {
+			return nil, err
+		}
+
+		b.logger.Warn("Reintentando request a Telegram API",
+			slog.String("method", method),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+
+		if err := b.clock.Sleep(ctx, delay); err != nil {
+			return nil, err
+		}
 	}
+}
+
+// doRequest realiza un único intento de llamada al método de la API.
+func (b *Bot) doRequest(ctx context.Context, method string, body io.Reader, contentType string) (*Response, error) {
+	url := fmt.Sprintf(b.apiBaseURL, b.token, method)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
@@ -131,7 +200,7 @@ func (b *Bot) makeRequest(ctx context.Context, method string, payload any) (*Res
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	b.logger.Debug("Enviando request a Telegram API",
 		slog.String("method", method),
@@ -143,7 +212,7 @@ func (b *Bot) makeRequest(ctx context.Context, method string, payload any) (*Res
 			slog.String("method", method),
 			slog.String("error", err.Error()),
 		)
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, &networkError{op: "making request", err: err}
 	}
 	defer resp.Body.Close()
 
@@ -153,140 +222,258 @@ func (b *Bot) makeRequest(ctx context.Context, method string, payload any) (*Res
 			slog.String("method", method),
 			slog.String("error", err.Error()),
 		)
-		return nil, fmt.Errorf("error reading response: %w", err)
+		return nil, &networkError{op: "reading response", err: err}
 	}
 
 	var apiResp Response
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
 		b.logger.Error("Error unmarshaling response",
 			slog.String("method", method),
+			slog.Int("status", resp.StatusCode),
 			slog.String("error", err.Error()),
 		)
+		// Un proxy o balanceador frente a la API puede responder 5xx sin JSON
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &APIError{
+				Method:      method,
+				Code:        resp.StatusCode,
+				Description: http.StatusText(resp.StatusCode),
+			}
+		}
 		return nil, fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if !apiResp.Ok {
 		b.logger.Error("API error response",
 			slog.String("method", method),
+			slog.Int("error_code", apiResp.ErrorCode),
 			slog.String("description", apiResp.Description),
 		)
-		return nil, fmt.Errorf("API error: %s", apiResp.Description)
+		return nil, newAPIError(method, &apiResp)
 	}
 
 	return &apiResp, nil
 }
 
 func (b *Bot) getUpdates(ctx context.Context) ([]Update, error) {
+	return b.pollUpdates(ctx, b.offset)
+}
+
+// pollUpdates pide las actualizaciones a partir de offset.
+func (b *Bot) pollUpdates(ctx context.Context, offset int) ([]Update, error) {
 	params := map[string]interface{}{
-		"offset":  b.offset,
+		"offset":  offset,
 		"timeout": timeout,
 	}
+	if len(b.allowedUpdates) > 0 {
+		params["allowed_updates"] = b.allowedUpdates
+	}
 
 	resp, err := b.makeRequest(ctx, "getUpdates", params)
 	if err != nil {
 		return nil, err
 	}
 
-	var updates []Update
-	if err := json.Unmarshal(resp.Result, &updates); err != nil {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(resp.Result, &raws); err != nil {
 		return nil, fmt.Errorf("error unmarshaling updates: %w", err)
 	}
 
+	updates := make([]Update, 0, len(raws))
+	for _, raw := range raws {
+		update, err := decodeUpdate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling updates: %w", err)
+		}
+		updates = append(updates, *update)
+	}
+
 	return updates, nil
 }
 
-func (b *Bot) SendMessage(ctx context.Context, chatID int64, text string) error {
+// SendMessage envía un mensaje de texto y retorna el mensaje enviado, cuyo
+// MessageID permite editarlo, borrarlo o fijarlo más tarde.
+func (b *Bot) SendMessage(ctx context.Context, chatID int64, text string, opts ...SendOption) (*Message, error) {
 	payload := SendMessageRequest{
 		ChatID: chatID,
 		Text:   text,
 	}
+	payload.apply(opts)
 
-	_, err := b.makeRequest(ctx, "sendMessage", payload)
-	return err
+	return b.sendRequest(ctx, "sendMessage", payload)
 }
 
-func (b *Bot) GetMe(ctx context.Context) error {
+// sendRequest ejecuta un método de envío y decodifica el mensaje que
+// Telegram retorna como resultado.
+func (b *Bot) sendRequest(ctx context.Context, method string, payload any) (*Message, error) {
+	resp, err := b.makeRequest(ctx, method, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg Message
+	if err := json.Unmarshal(resp.Result, &msg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// GetMe obtiene el usuario del bot y lo guarda para que esté disponible
+// con Me. Start lo llama al iniciar para verificar el token.
+func (b *Bot) GetMe(ctx context.Context) (*User, error) {
 	resp, err := b.makeRequest(ctx, "getMe", nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var user User
 	if err := json.Unmarshal(resp.Result, &user); err != nil {
-		return err
+		return nil, fmt.Errorf("error unmarshaling user: %w", err)
 	}
+	b.me.Store(&user)
 
-	b.logger.Info("Bot iniciado",
-		slog.String("username", user.Username),
-		slog.String("first_name", user.FirstName),
-	)
-	return nil
+	return &user, nil
+}
+
+// Me retorna el usuario del bot obtenido con GetMe, o nil si todavía no se
+// llamó a GetMe ni a Start. Permite armar deep links (t.me/<username>) o
+// detectar menciones al bot.
+func (b *Bot) Me() *User {
+	return b.me.Load()
 }
 
-func (b *Bot) handleMessage(ctx context.Context, msg *Message) {
+// addressedToMe indica si el comando debe ser atendido por este bot: los
+// comandos sin mención se atienden siempre, y los que mencionan a un bot solo
+// si la mención coincide con el username obtenido con GetMe. Si el username
+// todavía no se conoce, se atienden todos.
+func (b *Bot) addressedToMe(cmd *ParsedCommand) bool {
+	if cmd.Mention == "" {
+		return true
+	}
+
+	me := b.me.Load()
+	if me == nil || me.Username == "" {
+		return true
+	}
+
+	if b.mentionFold {
+		return strings.EqualFold(cmd.Mention, me.Username)
+	}
+	return cmd.Mention == me.Username
+}
+
+// handleMessage procesa un mensaje: los comandos se ejecutan con el
+// CommandRegistry, los comandos no registrados con el handler de OnUnknownCommand
+// y el resto de los mensajes con el de OnMessage. Retorna false si ningún
+// handler atendió el mensaje, y el error del handler.
+func (b *Bot) handleMessage(ctx context.Context, msg *Message) (bool, error) {
 	b.logger.Info("Mensaje recibido",
 		slog.String("from", msg.From.FirstName),
 		slog.Int64("chat_id", msg.Chat.ID),
 		slog.String("text", msg.Text),
 	)
 
-	if strings.HasPrefix(msg.Text, "/") {
-		if b.commandRegistry != nil {
-			b.commandRegistry.Execute(ctx, b, msg)
-		}
-		return
+	cmd := msg.Command()
+	if cmd == nil {
+		return dispatch(ctx, b, b.handlers.message, msg)
 	}
 
-	if msg.Text != "" {
-		response := fmt.Sprintf("Recibí tu mensaje: %s", msg.Text)
-		if err := b.SendMessage(ctx, msg.Chat.ID, response); err != nil {
-			b.logger.Error("Error enviando mensaje",
-				slog.Int64("chat_id", msg.Chat.ID),
-				slog.String("error", err.Error()),
-			)
+	// Los comandos para otros bots no son para este bot: se ignoran
+	if !b.addressedToMe(cmd) {
+		return true, nil
+	}
+
+	if b.commandRegistry != nil {
+		if handled, err := b.commandRegistry.execute(ctx, b, msg); handled {
+			return true, err
 		}
 	}
+
+	return dispatch(ctx, b, b.handlers.unknownCommand, msg)
 }
 
 func (b *Bot) Start(ctx context.Context) error {
 	b.logger.Info("Iniciando bot...")
 
 	// Verificar que el token funciona
-	if err := b.GetMe(ctx); err != nil {
+	me, err := b.GetMe(ctx)
+	if err != nil {
 		return fmt.Errorf("error verificando bot: %w", err)
 	}
 
-	b.logger.Info("Esperando mensajes... (Ctrl+C para detener)")
-
-	for {
-		select {
-		case <-ctx.Done():
-			b.logger.Info("Shutdown señalizado, cerrando bot...")
-			return ctx.Err()
-		default:
-			updates, err := b.getUpdates(ctx)
-			if err != nil {
-				if ctx.Err() != nil {
-					// El contexto fue cancelado, salir limpiamente
-					return ctx.Err()
-				}
-				b.logger.Error("Error obteniendo updates",
-					slog.String("error", err.Error()),
-				)
-				time.Sleep(3 * time.Second)
-				continue
+	b.logger.Info("Bot iniciado",
+		slog.Int64("id", me.ID),
+		slog.String("username", me.Username),
+		slog.String("first_name", me.FirstName),
+	)
+
+	if err := b.loadOffset(ctx); err != nil {
+		return fmt.Errorf("error cargando offset: %w", err)
+	}
+
+	b.logger.Info("Esperando mensajes... (Ctrl+C para detener)",
+		slog.Int("offset", b.offset),
+	)
+
+	// Los handlers no usan ctx directamente: al cancelarse ctx deben poder
+	// terminar sus envíos durante el período de gracia
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
+	d := newDispatcher(b.dispatch, func(update *Update) bool {
+		return b.processUpdate(handlerCtx, update)
+	})
+
+	for ctx.Err() == nil {
+		updates, err := b.pollUpdates(ctx, b.pollOffset(d))
+		if err != nil {
+			if ctx.Err() != nil {
+				// El contexto fue cancelado, salir limpiamente
+				break
+			}
+			b.logger.Error("Error obteniendo updates",
+				slog.String("error", err.Error()),
+			)
+			select {
+			case <-ctx.Done():
+			case <-time.After(3 * time.Second):
 			}
+			continue
+		}
 
-			for _, update := range updates {
-				// Actualizar offset para el próximo request
-				b.offset = update.UpdateID + 1
+		submitted := 0
+		for _, update := range updates {
+			// En modo ack Telegram vuelve a entregar las actualizaciones sin
+			// confirmar, que ya están en el dispatcher
+			if b.ackPolicy != nil && update.UpdateID < b.offset {
+				continue
+			}
 
-				// Procesar mensaje en goroutine para no bloquear
-				if update.Message != nil {
-					msg := update.Message
-					go b.handleMessage(ctx, msg)
-				}
+			// Si la cola está llena, submit espera y el polling se pausa
+			if err := d.submit(ctx, &update); err != nil {
+				break
 			}
+			submitted++
+
+			// Actualizar offset para el próximo request
+			b.offset = update.UpdateID + 1
+		}
+
+		if len(updates) > 0 {
+			b.saveOffset(ctx, b.pollOffset(d))
+		}
+
+		// Si el lote solo trajo actualizaciones que siguen en proceso, pedirlo
+		// de nuevo enseguida las volvería a traer
+		if len(updates) > 0 && submitted == 0 {
+			d.waitProgress(ctx)
 		}
 	}
+
+	b.logger.Info("Shutdown señalizado, cerrando bot...")
+	b.shutdown(d, cancelHandlers)
+	b.logger.Info("Bot detenido")
+
+	return ctx.Err()
 }
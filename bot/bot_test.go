@@ -287,9 +287,14 @@ func TestBot_SendMessage(t *testing.T) {
 		}
 
 	ctx := context.Background()
-	err := bot.SendMessage(ctx, 123, "Hello")
+	msg, err := bot.SendMessage(ctx, 123, "Hello")
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	if msg.MessageID != 1 {
+		t.Errorf("expected MessageID 1, got %d", msg.MessageID)
 	}
 }
 
@@ -308,7 +313,7 @@ func TestBot_SendMessage_Error(t *testing.T) {
 		}
 
 	ctx := context.Background()
-	err := bot.SendMessage(ctx, 123, "Hello")
+	_, err := bot.SendMessage(ctx, 123, "Hello")
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
@@ -323,7 +328,7 @@ func TestBot_GetMe(t *testing.T) {
 	}{
 		{
 			name:        "successful GetMe",
-			response:    `{"ok":true,"result":{"id":123,"first_name":"TestBot","username":"testbot"}}`,
+			response:    `{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"TestBot","username":"testbot","can_join_groups":true,"can_read_all_group_messages":false,"supports_inline_queries":true}}`,
 			wantErr:     false,
 		},
 		{
@@ -355,7 +360,7 @@ func TestBot_GetMe(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			err := bot.GetMe(ctx)
+			user, err := bot.GetMe(ctx)
 
 			if tt.wantErr {
 				if err == nil {
@@ -369,6 +374,14 @@ func TestBot_GetMe(t *testing.T) {
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
+
+			if user == nil || user.ID != 123 || !user.CanJoinGroups || !user.SupportsInlineQueries {
+				t.Errorf("unexpected bot user %+v", user)
+			}
+
+			if me := bot.Me(); me == nil || me.Username != "testbot" {
+				t.Errorf("expected bot user to be cached, got %+v", me)
+			}
 		})
 	}
 }
@@ -384,9 +397,10 @@ func TestBot_handleMessage(t *testing.T) {
 		{
 			name: "message with command and registry",
 			msg: &Message{
-				Text: "/start",
-				From: &User{FirstName: "Test"},
-				Chat: &Chat{ID: 123},
+				Text:     "/start",
+				Entities: []MessageEntity{{Type: "bot_command", Offset: 0, Length: 6}},
+				From:     &User{FirstName: "Test"},
+				Chat:     &Chat{ID: 123},
 			},
 			hasRegistry: true,
 			commandHandler: func(ctx context.Context, b *Bot, m *Message) {
@@ -451,10 +465,9 @@ func TestBot_handleMessage(t *testing.T) {
 			// Give goroutines time to complete
 			time.Sleep(50 * time.Millisecond)
 
-			if !strings.HasPrefix(tt.msg.Text, "/") && tt.msg.Text != "" {
-				if !strings.Contains(sentMessage, tt.msg.Text) {
-					t.Errorf("expected message to contain %q, got %q", tt.msg.Text, sentMessage)
-				}
+			// Sin handlers registrados el bot no responde a los mensajes
+			if sentMessage != "" {
+				t.Errorf("expected no reply, got %q", sentMessage)
 			}
 		})
 	}
@@ -463,9 +476,10 @@ func TestBot_handleMessage(t *testing.T) {
 func TestBot_handleMessage_NilRegistry(t *testing.T) {
 	// Test that handleMessage doesn't panic when registry is nil
 	msg := &Message{
-		Text: "/start",
-		From: &User{FirstName: "Test"},
-		Chat: &Chat{ID: 123},
+		Text:     "/start",
+		Entities: []MessageEntity{{Type: "bot_command", Offset: 0, Length: 6}},
+		From:     &User{FirstName: "Test"},
+		Chat:     &Chat{ID: 123},
 	}
 
 	bot := &Bot{
@@ -541,3 +555,26 @@ func TestBot_Start_GetMeError(t *testing.T) {
 	}
 }
 
+func TestBot_SendMessage_InvalidResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":"invalid"}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	msg, err := bot.SendMessage(context.Background(), 123, "Hello")
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if msg != nil {
+		t.Errorf("expected nil message, got %+v", msg)
+	}
+}
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+type (
+	// CallbackRegistry enruta los callback queries de teclados inline según
+	// su callback_data. Se busca primero una coincidencia exacta, luego el
+	// prefijo registrado más largo y por último los patrones en el orden en
+	// que fueron registrados.
+	CallbackRegistry struct {
+		exact    map[string]CallbackHandler
+		prefixes []callbackPrefix
+		patterns []callbackPattern
+	}
+	// CallbackHandler procesa un callback query. El error se registra en el
+	// log y, con WithAckMode, hace que el update se reintente.
+	CallbackHandler func(context.Context, *Bot, *CallbackQuery) error
+
+	callbackPrefix struct {
+		prefix  string
+		handler CallbackHandler
+	}
+
+	callbackPattern struct {
+		pattern *regexp.Regexp
+		handler CallbackHandler
+	}
+)
+
+// WithCallbackRegistry configura el registro de callback queries del bot.
+// Los callback queries que no coinciden con ninguna ruta se pasan al handler
+// de OnCallbackQuery, si existe.
+//
+// Ejemplo:
+//
+//	callbacks := bot.NewCallbackRegistry()
+//	callbacks.RegisterPrefix("confirm:", handleConfirm)
+//	bot := bot.NewBot(token, bot.WithCallbackRegistry(callbacks))
+func WithCallbackRegistry(registry *CallbackRegistry) BotOption {
+	return func(b *Bot) {
+		b.callbackRegistry = registry
+	}
+}
+
+func NewCallbackRegistry() *CallbackRegistry {
+	return &CallbackRegistry{
+		exact: make(map[string]CallbackHandler),
+	}
+}
+
+// Register asocia el handler a un callback_data exacto.
+func (cr *CallbackRegistry) Register(data string, action CallbackHandler) {
+	cr.exact[data] = action
+}
+
+// RegisterPrefix asocia el handler a todo callback_data que empiece con prefix.
+// Registrar el mismo prefijo dos veces reemplaza el handler anterior.
+func (cr *CallbackRegistry) RegisterPrefix(prefix string, action CallbackHandler) {
+	for i := range cr.prefixes {
+		if cr.prefixes[i].prefix == prefix {
+			cr.prefixes[i].handler = action
+			return
+		}
+	}
+	cr.prefixes = append(cr.prefixes, callbackPrefix{prefix: prefix, handler: action})
+}
+
+// RegisterPattern asocia el handler a todo callback_data que coincida con la
+// expresión regular.
+func (cr *CallbackRegistry) RegisterPattern(pattern *regexp.Regexp, action CallbackHandler) {
+	cr.patterns = append(cr.patterns, callbackPattern{pattern: pattern, handler: action})
+}
+
+// Execute ejecuta el handler correspondiente al callback query, si existe.
+// El error del handler se registra en el log.
+func (cr *CallbackRegistry) Execute(ctx context.Context, bot *Bot, query *CallbackQuery) bool {
+	handled, err := cr.execute(ctx, bot, query)
+	if err != nil {
+		bot.logger.Error("Error ejecutando callback",
+			slog.String("data", query.Data),
+			slog.String("error", err.Error()),
+		)
+	}
+	return handled
+}
+
+// execute ejecuta el handler correspondiente al callback query. Retorna false
+// si ninguna ruta coincide.
+func (cr *CallbackRegistry) execute(ctx context.Context, bot *Bot, query *CallbackQuery) (bool, error) {
+	action := cr.match(query.Data)
+	if action == nil {
+		return false, nil
+	}
+
+	return true, action(ctx, bot, query)
+}
+
+func (cr *CallbackRegistry) match(data string) CallbackHandler {
+	if action, exists := cr.exact[data]; exists {
+		return action
+	}
+
+	var (
+		action  CallbackHandler
+		longest = -1
+	)
+	for _, route := range cr.prefixes {
+		if strings.HasPrefix(data, route.prefix) && len(route.prefix) > longest {
+			action = route.handler
+			longest = len(route.prefix)
+		}
+	}
+	if action != nil {
+		return action
+	}
+
+	for _, route := range cr.patterns {
+		if route.pattern.MatchString(data) {
+			return route.handler
+		}
+	}
+
+	return nil
+}
+
+// AnswerCallbackQuery responde a un callback query. Telegram muestra un
+// indicador de carga en el botón hasta que se responde, por lo que todo
+// handler de callback debería llamarlo aunque sea sin texto.
+func (b *Bot) AnswerCallbackQuery(ctx context.Context, req AnswerCallbackQueryRequest) error {
+	_, err := b.makeRequest(ctx, "answerCallbackQuery", req)
+	return err
+}
+
+// handleCallbackQuery procesa un callback query con el registro de callbacks
+// y, si ninguna ruta coincide, con el handler de OnCallbackQuery.
+func (b *Bot) handleCallbackQuery(ctx context.Context, query *CallbackQuery) (bool, error) {
+	if b.callbackRegistry != nil {
+		if handled, err := b.callbackRegistry.execute(ctx, b, query); handled {
+			return true, err
+		}
+	}
+	return dispatch(ctx, b, b.handlers.callbackQuery, query)
+}
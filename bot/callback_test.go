@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCallbackRegistry_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantExecuted bool
+		wantRoute    string
+	}{
+		{
+			name:         "exact match",
+			data:         "menu",
+			wantExecuted: true,
+			wantRoute:    "exact",
+		},
+		{
+			name:         "exact match wins over prefix",
+			data:         "confirm:yes",
+			wantExecuted: true,
+			wantRoute:    "exact-confirm",
+		},
+		{
+			name:         "prefix match",
+			data:         "confirm:no",
+			wantExecuted: true,
+			wantRoute:    "prefix",
+		},
+		{
+			name:         "longest prefix wins",
+			data:         "confirm:delete:42",
+			wantExecuted: true,
+			wantRoute:    "long-prefix",
+		},
+		{
+			name:         "pattern match",
+			data:         "page:7",
+			wantExecuted: true,
+			wantRoute:    "pattern",
+		},
+		{
+			name:         "no match",
+			data:         "unknown",
+			wantExecuted: false,
+		},
+		{
+			name:         "empty data",
+			data:         "",
+			wantExecuted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var route string
+			handler := func(name string) CallbackHandler {
+				return func(ctx context.Context, b *Bot, q *CallbackQuery) error {
+					route = name
+					return nil
+				}
+			}
+
+			registry := NewCallbackRegistry()
+			registry.Register("menu", handler("exact"))
+			registry.Register("confirm:yes", handler("exact-confirm"))
+			registry.RegisterPrefix("confirm:", handler("prefix"))
+			registry.RegisterPrefix("confirm:delete:", handler("long-prefix"))
+			registry.RegisterPattern(regexp.MustCompile(`^page:\d+$`), handler("pattern"))
+
+			query := &CallbackQuery{ID: "1", Data: tt.data}
+			executed := registry.Execute(context.Background(), NewBot("test-token"), query)
+
+			if executed != tt.wantExecuted {
+				t.Errorf("expected executed=%v, got %v", tt.wantExecuted, executed)
+			}
+
+			if route != tt.wantRoute {
+				t.Errorf("expected route %q, got %q", tt.wantRoute, route)
+			}
+		})
+	}
+}
+
+func TestCallbackRegistry_Execute_Error(t *testing.T) {
+	registry := NewCallbackRegistry()
+	registry.Register("menu", func(ctx context.Context, b *Bot, q *CallbackQuery) error {
+		return errors.New("boom")
+	})
+
+	// Execute registra el error y sigue informando que la ruta existe
+	if !registry.Execute(context.Background(), NewBot("test-token", WithLogger(testLogger())), &CallbackQuery{Data: "menu"}) {
+		t.Error("expected the failing route to count as executed")
+	}
+}
+
+func TestCallbackRegistry_RegisterPrefix_Overwrite(t *testing.T) {
+	registry := NewCallbackRegistry()
+
+	calls := 0
+	registry.RegisterPrefix("a:", func(ctx context.Context, b *Bot, q *CallbackQuery) error {
+		t.Error("expected first handler to be replaced")
+		return nil
+	})
+	registry.RegisterPrefix("a:", func(ctx context.Context, b *Bot, q *CallbackQuery) error { calls++; return nil })
+
+	registry.Execute(context.Background(), NewBot("test-token"), &CallbackQuery{Data: "a:1"})
+
+	if len(registry.prefixes) != 1 {
+		t.Errorf("expected 1 prefix route, got %d", len(registry.prefixes))
+	}
+
+	if calls != 1 {
+		t.Errorf("expected second handler to be called once, got %d", calls)
+	}
+}
+
+func TestBot_handleUpdate_CallbackQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantRegistry bool
+		wantFallback bool
+	}{
+		{
+			name:         "routed by registry",
+			data:         "confirm:yes",
+			wantRegistry: true,
+		},
+		{
+			name:         "falls back to OnCallbackQuery",
+			data:         "other",
+			wantFallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRegistry, gotFallback bool
+
+			registry := NewCallbackRegistry()
+			registry.RegisterPrefix("confirm:", func(ctx context.Context, b *Bot, q *CallbackQuery) error { gotRegistry = true; return nil })
+
+			bot := NewBot("test-token",
+				WithLogger(testLogger()),
+				WithCallbackRegistry(registry),
+				OnCallbackQuery(func(ctx context.Context, b *Bot, q *CallbackQuery) error { gotFallback = true; return nil }),
+			)
+
+			bot.handleUpdate(context.Background(), &Update{
+				UpdateID:      1,
+				CallbackQuery: &CallbackQuery{ID: "1", From: &User{ID: 1}, Data: tt.data},
+			})
+
+			if gotRegistry != tt.wantRegistry {
+				t.Errorf("expected registry called=%v, got %v", tt.wantRegistry, gotRegistry)
+			}
+
+			if gotFallback != tt.wantFallback {
+				t.Errorf("expected fallback called=%v, got %v", tt.wantFallback, gotFallback)
+			}
+		})
+	}
+}
+
+func TestBot_AnswerCallbackQuery(t *testing.T) {
+	var received AnswerCallbackQueryRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	err := bot.AnswerCallbackQuery(context.Background(), AnswerCallbackQueryRequest{
+		CallbackQueryID: "42",
+		Text:            "Listo",
+		ShowAlert:       true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.CallbackQueryID != "42" || received.Text != "Listo" || !received.ShowAlert {
+		t.Errorf("unexpected request: %+v", received)
+	}
+}
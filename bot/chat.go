@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// GetChatMember obtiene la información de un miembro de un chat, incluido su
+// Status ("creator", "administrator", "member", "restricted", "left" o
+// "kicked").
+func (b *Bot) GetChatMember(ctx context.Context, chatID, userID int64) (*ChatMember, error) {
+	params := map[string]int64{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	resp, err := b.makeRequest(ctx, "getChatMember", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var member ChatMember
+	if err := json.Unmarshal(resp.Result, &member); err != nil {
+		return nil, fmt.Errorf("error unmarshaling chat member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// isChatAdmin indica si quien envió el mensaje administra el chat. Los
+// administradores anónimos envían mensajes en nombre del propio grupo. En
+// chats privados no hay administradores.
+func (b *Bot) isChatAdmin(ctx context.Context, msg *Message) bool {
+	if msg.Chat.Type == "private" || msg.Chat.Type == "" {
+		return false
+	}
+
+	if msg.SenderChat != nil && msg.SenderChat.ID == msg.Chat.ID {
+		return true
+	}
+
+	if msg.From == nil {
+		return false
+	}
+
+	member, err := b.GetChatMember(ctx, msg.Chat.ID, msg.From.ID)
+	if err != nil {
+		b.logger.Error("Error obteniendo miembro del chat",
+			slog.Int64("chat_id", msg.Chat.ID),
+			slog.Int64("user_id", msg.From.ID),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+
+	return member.Status == "creator" || member.Status == "administrator"
+}
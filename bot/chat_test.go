@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBot_GetChatMember(t *testing.T) {
+	var params map[string]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottest-token/getChatMember" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&params)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":{"status":"administrator","user":{"id":7,"is_bot":false,"first_name":"Ana"}}}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	member, err := bot.GetChatMember(context.Background(), -100, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if params["chat_id"] != -100 || params["user_id"] != 7 {
+		t.Errorf("unexpected params %v", params)
+	}
+
+	if member.Status != "administrator" || member.User == nil || member.User.ID != 7 {
+		t.Errorf("unexpected member %+v", member)
+	}
+}
@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"context"
+	"time"
+)
+
+// clock abstrae el paso del tiempo para poder testear reintentos y rate
+// limiting de forma determinística.
+type clock interface {
+	Now() time.Time
+	// Sleep espera la duración indicada o hasta que el contexto sea cancelado.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock implementa clock usando el reloj del sistema.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -2,45 +2,258 @@ package bot
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"strings"
+	"unicode/utf16"
 )
 
 type (
 	CommandRegistry struct {
-		registry map[string]Command
+		registry map[string]*commandEntry
+		names    []string // Orden de registro, usado en el menú de comandos
 	}
 	Command func(context.Context, *Bot, *Message)
+
+	// CommandHandler es un handler de comando que puede fallar. El error se
+	// registra en el log y, con WithAckMode, hace que el update se reintente.
+	CommandHandler func(context.Context, *Bot, *Message) error
+
+	// CommandOption configura un comando al registrarlo.
+	CommandOption func(*commandEntry)
+
+	commandEntry struct {
+		action       CommandHandler
+		args         argSchema
+		description  string
+		descriptions map[string]string // Descripciones por código de idioma
+		scopes       []BotCommandScope
+		category     string
+		adminOnly    bool
+		middlewares  []Middleware
+	}
 )
 
-func NewCommandRegistry() *CommandRegistry {
-	return &CommandRegistry{
-		registry: make(map[string]Command),
+// WithDescription configura la descripción del comando en el menú del
+// cliente. Solo los comandos con descripción se publican con SyncCommands.
+func WithDescription(description string) CommandOption {
+	return func(e *commandEntry) {
+		e.description = description
 	}
 }
 
-func (cr *CommandRegistry) Register(command string, action Command) {
-	cr.registry[command] = action
+// WithLocalizedDescription configura la descripción del comando para los
+// usuarios con el idioma indicado (código IETF de dos letras, como "en").
+func WithLocalizedDescription(languageCode, description string) CommandOption {
+	return func(e *commandEntry) {
+		if e.descriptions == nil {
+			e.descriptions = make(map[string]string)
+		}
+		e.descriptions[languageCode] = description
+	}
 }
 
-func (cr *CommandRegistry) Execute(ctx context.Context, bot *Bot, msg *Message) bool {
-	if !strings.HasPrefix(msg.Text, "/") {
-		return false
+// WithScope indica en qué chats se muestra el comando en el menú. Por
+// defecto se muestra en todos (ScopeDefault).
+//
+// Ejemplo:
+//
+//	commands.Register("ban", commandBan,
+//	    bot.WithDescription("Expulsa a un usuario"),
+//	    bot.WithScope(bot.ScopeAllChatAdministrators()),
+//	)
+func WithScope(scopes ...BotCommandScope) CommandOption {
+	return func(e *commandEntry) {
+		e.scopes = append(e.scopes, scopes...)
+	}
+}
+
+// WithArgs declara los argumentos del comando. Antes de ejecutarlo, el texto
+// que sigue al comando se interpreta según la lista y el resultado queda
+// disponible con ArgsFromContext. Si los argumentos no son válidos, el bot
+// responde con el error y la forma de uso, y el handler no se ejecuta.
+//
+// Ejemplo:
+//
+//	commands.Register("mute", commandMute, bot.WithArgs(
+//	    bot.StringArg("user"),
+//	    bot.DurationArg("duration"),
+//	    bot.StringArg("reason").Optional(),
+//	))
+func WithArgs(specs ...ArgSpec) CommandOption {
+	return func(e *commandEntry) {
+		e.args = specs
 	}
+}
 
-	parts := strings.Fields(msg.Text)
-	if len(parts) == 0 {
-		return false
+func NewCommandRegistry(opts ...RegistryOption) *CommandRegistry {
+	cr := &CommandRegistry{
+		registry: make(map[string]*commandEntry),
 	}
 
-	command := strings.TrimPrefix(parts[0], "/")
-	// Remover @botname si está presente
-	command = strings.Split(command, "@")[0]
+	for _, opt := range opts {
+		opt(cr)
+	}
+
+	return cr
+}
+
+// Register registra el handler de un comando. Hace panic si los argumentos
+// declarados con WithArgs no son coherentes, por ejemplo un argumento
+// obligatorio después de uno opcional.
+func (cr *CommandRegistry) Register(command string, action Command, opts ...CommandOption) {
+	cr.Handle(command, func(ctx context.Context, b *Bot, msg *Message) error {
+		action(ctx, b, msg)
+		return nil
+	}, opts...)
+}
+
+// Handle registra un handler de comando que retorna error. Se comporta igual
+// que Register.
+//
+// Ejemplo:
+//
+//	commands.Handle("pay", func(ctx context.Context, b *bot.Bot, msg *bot.Message) error {
+//	    if err := billing.Charge(ctx, msg.From.ID); err != nil {
+//	        return fmt.Errorf("charging user: %w", err)
+//	    }
+//	    _, err := b.SendMessage(ctx, msg.Chat.ID, "Pago registrado")
+//	    return err
+//	})
+func (cr *CommandRegistry) Handle(command string, action CommandHandler, opts ...CommandOption) {
+	entry := &commandEntry{action: action}
+	for _, opt := range opts {
+		opt(entry)
+	}
+
+	if err := entry.args.validate(); err != nil {
+		panic(fmt.Sprintf("bot: invalid arguments for command %q: %v", command, err))
+	}
+
+	if _, exists := cr.registry[command]; !exists {
+		cr.names = append(cr.names, command)
+	}
+	cr.registry[command] = entry
+}
+
+func (cr *CommandRegistry) Execute(ctx context.Context, bot *Bot, msg *Message) bool {
+	handled, err := cr.execute(ctx, bot, msg)
+	if err != nil {
+		bot.logger.Error("Error ejecutando comando",
+			slog.Int64("chat_id", msg.Chat.ID),
+			slog.String("text", msg.Text),
+			slog.String("error", err.Error()),
+		)
+	}
+	return handled
+}
+
+// execute ejecuta el comando del mensaje a través de sus middlewares. Retorna
+// false si el mensaje no tiene un comando registrado para este bot.
+func (cr *CommandRegistry) execute(ctx context.Context, bot *Bot, msg *Message) (bool, error) {
+	cmd := msg.Command()
+	if cmd == nil || !bot.addressedToMe(cmd) {
+		return false, nil
+	}
 
-	action, exists := cr.registry[command]
+	entry, exists := cr.registry[cmd.Name]
 	if !exists {
-		return false
+		return false, nil
+	}
+
+	// Fuera de handleUpdate, como al llamar a Execute directamente, los
+	// middlewares reciben un update armado con el mensaje
+	update := updateFromContext(ctx)
+	if update == nil {
+		update = &Update{Message: msg}
+	}
+
+	run := func(ctx context.Context, bot *Bot, _ *Update) error {
+		return entry.run(ctx, bot, msg, cmd)
+	}
+
+	return true, chain(run, entry.middlewares)(ctx, bot, update)
+}
+
+// run verifica los permisos y argumentos del comando y ejecuta su handler.
+func (e *commandEntry) run(ctx context.Context, bot *Bot, msg *Message, cmd *ParsedCommand) error {
+	if e.adminOnly && !bot.isChatAdmin(ctx, msg) {
+		bot.replyUsage(ctx, msg, "Este comando es solo para administradores.")
+		return nil
+	}
+
+	if e.args != nil {
+		args, err := e.args.parse(cmd.Args)
+		if err != nil {
+			bot.replyUsage(ctx, msg, fmt.Sprintf("%s\nUso: %s", err, e.args.usage(cmd.Name)))
+			return nil
+		}
+		ctx = context.WithValue(ctx, argsContextKey{}, args)
 	}
 
-	action(ctx, bot, msg)
-	return true
+	return e.action(ctx, bot, msg)
+}
+
+// replyUsage responde al mensaje del comando con un error de uso.
+func (b *Bot) replyUsage(ctx context.Context, msg *Message, text string) {
+	if _, err := b.SendMessage(ctx, msg.Chat.ID, text, WithReplyTo(msg.MessageID)); err != nil {
+		b.logger.Error("Error enviando mensaje",
+			slog.Int64("chat_id", msg.Chat.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// ParsedCommand es el comando con el que comienza un mensaje.
+//
+// Para "/remind@equipo_bot 10m revisar PR" contiene Name "remind",
+// Mention "equipo_bot" y Args "10m revisar PR".
+type ParsedCommand struct {
+	Name    string // Nombre del comando, sin "/" ni "@botname"
+	Mention string // Username del bot al que se dirige, sin "@"; vacío si no se indicó
+	Args    string // Texto que sigue al comando, sin espacios al inicio ni al final
+}
+
+// Command retorna el comando con el que comienza el texto del mensaje, o el
+// caption si el mensaje no tiene texto. El comando se detecta a partir de
+// las entidades bot_command que envía Telegram, por lo que textos como
+// "/usr/bin" no se consideran comandos. Retorna nil si el mensaje no
+// comienza con un comando.
+func (m *Message) Command() *ParsedCommand {
+	text, entities := m.Text, m.Entities
+	if text == "" {
+		text, entities = m.Caption, m.CaptionEntities
+	}
+
+	for _, entity := range entities {
+		if entity.Type != "bot_command" || entity.Offset != 0 {
+			continue
+		}
+
+		end := utf16Index(text, entity.Length)
+		name, mention, _ := strings.Cut(strings.TrimPrefix(text[:end], "/"), "@")
+		if name == "" {
+			return nil
+		}
+
+		return &ParsedCommand{
+			Name:    name,
+			Mention: mention,
+			Args:    strings.TrimSpace(text[end:]),
+		}
+	}
+
+	return nil
+}
+
+// utf16Index convierte una posición en unidades UTF-16, como las usadas por
+// los offsets de las entidades, al índice en bytes del string.
+func utf16Index(s string, units int) int {
+	for i, r := range s {
+		if units <= 0 {
+			return i
+		}
+		units -= utf16.RuneLen(r)
+	}
+	return len(s)
 }
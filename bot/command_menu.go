@@ -0,0 +1,226 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// ScopeDefault muestra los comandos en todos los chats que no tienen un menú
+// más específico.
+func ScopeDefault() BotCommandScope {
+	return BotCommandScope{Type: "default"}
+}
+
+// ScopeAllPrivateChats muestra los comandos en todos los chats privados.
+func ScopeAllPrivateChats() BotCommandScope {
+	return BotCommandScope{Type: "all_private_chats"}
+}
+
+// ScopeAllGroupChats muestra los comandos en todos los grupos y supergrupos.
+func ScopeAllGroupChats() BotCommandScope {
+	return BotCommandScope{Type: "all_group_chats"}
+}
+
+// ScopeAllChatAdministrators muestra los comandos a los administradores de
+// todos los grupos y supergrupos.
+func ScopeAllChatAdministrators() BotCommandScope {
+	return BotCommandScope{Type: "all_chat_administrators"}
+}
+
+// ScopeChat muestra los comandos en un chat específico.
+func ScopeChat(chatID int64) BotCommandScope {
+	return BotCommandScope{Type: "chat", ChatID: chatID}
+}
+
+// ScopeChatAdministrators muestra los comandos a los administradores de un
+// grupo o supergrupo específico.
+func ScopeChatAdministrators(chatID int64) BotCommandScope {
+	return BotCommandScope{Type: "chat_administrators", ChatID: chatID}
+}
+
+// ScopeChatMember muestra los comandos a un miembro específico de un grupo.
+func ScopeChatMember(chatID, userID int64) BotCommandScope {
+	return BotCommandScope{Type: "chat_member", ChatID: chatID, UserID: userID}
+}
+
+// SetMyCommands reemplaza el menú de comandos del alcance e idioma indicados.
+func (b *Bot) SetMyCommands(ctx context.Context, req SetMyCommandsRequest) error {
+	_, err := b.makeRequest(ctx, "setMyCommands", req)
+	return err
+}
+
+// DeleteMyCommands elimina el menú de comandos del alcance e idioma
+// indicados; los usuarios pasan a ver el menú del alcance más general.
+func (b *Bot) DeleteMyCommands(ctx context.Context, req MyCommandsRequest) error {
+	_, err := b.makeRequest(ctx, "deleteMyCommands", req)
+	return err
+}
+
+// GetMyCommands obtiene el menú de comandos del alcance e idioma indicados.
+func (b *Bot) GetMyCommands(ctx context.Context, req MyCommandsRequest) ([]BotCommand, error) {
+	resp, err := b.makeRequest(ctx, "getMyCommands", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []BotCommand
+	if err := json.Unmarshal(resp.Result, &commands); err != nil {
+		return nil, fmt.Errorf("error unmarshaling commands: %w", err)
+	}
+
+	return commands, nil
+}
+
+// menuKey identifica un menú de comandos: un alcance y un idioma, donde el
+// idioma vacío es el menú para todos los idiomas.
+type menuKey struct {
+	scope        BotCommandScope
+	languageCode string
+}
+
+// generalScopes son los alcances que no dependen de un chat. Como la API no
+// permite listar los menús configurados, SyncCommands revisa siempre estos
+// alcances para eliminar los menús que ya no corresponden al registro.
+var generalScopes = []BotCommandScope{
+	ScopeDefault(),
+	ScopeAllPrivateChats(),
+	ScopeAllGroupChats(),
+	ScopeAllChatAdministrators(),
+}
+
+// SyncCommands publica el menú de comandos a partir del registro, de modo que
+// coincida exactamente con los comandos registrados con WithDescription. Los
+// menús que ya coinciden no se modifican, y los de alcances generales que ya
+// no tienen comandos se eliminan.
+//
+// Los menús de chats específicos o de idiomas que ya no están en el registro
+// no se pueden detectar; para quitarlos hay que usar DeleteMyCommands.
+//
+// Ejemplo:
+//
+//	if err := b.SyncCommands(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	b.Start(ctx)
+func (b *Bot) SyncCommands(ctx context.Context) error {
+	if b.commandRegistry == nil {
+		return errors.New("command registry not configured")
+	}
+
+	menus := b.commandRegistry.menus()
+
+	languages := map[string]bool{"": true}
+	for key := range menus {
+		languages[key.languageCode] = true
+	}
+
+	keys := slices.Collect(maps.Keys(menus))
+	for _, scope := range generalScopes {
+		for lang := range languages {
+			key := menuKey{scope: scope, languageCode: lang}
+			if _, ok := menus[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.SortFunc(keys, compareMenuKeys)
+
+	for _, key := range keys {
+		scope := key.scope
+		current, err := b.GetMyCommands(ctx, MyCommandsRequest{Scope: &scope, LanguageCode: key.languageCode})
+		if err != nil {
+			return err
+		}
+
+		want := menus[key]
+		if slices.Equal(current, want) {
+			continue
+		}
+
+		b.logger.Info("Actualizando menú de comandos",
+			slog.String("scope", scope.Type),
+			slog.Int64("chat_id", scope.ChatID),
+			slog.String("language_code", key.languageCode),
+			slog.Int("commands", len(want)),
+		)
+
+		if len(want) == 0 {
+			err = b.DeleteMyCommands(ctx, MyCommandsRequest{Scope: &scope, LanguageCode: key.languageCode})
+		} else {
+			err = b.SetMyCommands(ctx, SetMyCommandsRequest{Commands: want, Scope: &scope, LanguageCode: key.languageCode})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// menus arma los menús de comandos del registro, con los comandos en orden
+// de registro. Además del menú para todos los idiomas, cada alcance tiene un
+// menú por idioma con descripciones localizadas, completado con las
+// descripciones por defecto.
+func (cr *CommandRegistry) menus() map[menuKey][]BotCommand {
+	menus := make(map[menuKey][]BotCommand)
+
+	for _, name := range cr.names {
+		entry := cr.registry[name]
+		if entry.description == "" {
+			continue
+		}
+
+		languages := append([]string{""}, slices.Collect(maps.Keys(entry.descriptions))...)
+		for _, scope := range entry.menuScopes() {
+			for _, lang := range languages {
+				key := menuKey{scope: scope, languageCode: lang}
+				if _, ok := menus[key]; !ok {
+					menus[key] = cr.menu(scope, lang)
+				}
+			}
+		}
+	}
+
+	return menus
+}
+
+// menu arma el menú de un alcance en un idioma.
+func (cr *CommandRegistry) menu(scope BotCommandScope, lang string) []BotCommand {
+	var commands []BotCommand
+	for _, name := range cr.names {
+		entry := cr.registry[name]
+		if entry.description == "" || !slices.Contains(entry.menuScopes(), scope) {
+			continue
+		}
+
+		commands = append(commands, BotCommand{Command: name, Description: entry.localizedDescription(lang)})
+	}
+	return commands
+}
+
+// menuScopes retorna los alcances donde se muestra el comando. Sin WithScope,
+// los comandos AdminOnly se muestran solo a los administradores.
+func (e *commandEntry) menuScopes() []BotCommandScope {
+	if len(e.scopes) > 0 {
+		return e.scopes
+	}
+	if e.adminOnly {
+		return []BotCommandScope{ScopeAllChatAdministrators()}
+	}
+	return []BotCommandScope{ScopeDefault()}
+}
+
+func compareMenuKeys(a, b menuKey) int {
+	return cmp.Or(
+		cmp.Compare(a.scope.Type, b.scope.Type),
+		cmp.Compare(a.scope.ChatID, b.scope.ChatID),
+		cmp.Compare(a.scope.UserID, b.scope.UserID),
+		cmp.Compare(a.languageCode, b.languageCode),
+	)
+}
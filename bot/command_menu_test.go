@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCommandMenus simula los menús de comandos guardados en Telegram.
+type fakeCommandMenus struct {
+	menus map[menuKey][]BotCommand
+	calls []string
+}
+
+func (f *fakeCommandMenus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req SetMyCommandsRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	key := menuKey{scope: ScopeDefault(), languageCode: req.LanguageCode}
+	if req.Scope != nil {
+		key.scope = *req.Scope
+	}
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	f.calls = append(f.calls, method)
+
+	var result any = true
+	switch method {
+	case "getMyCommands":
+		result = f.menus[key]
+		if result == nil {
+			result = []BotCommand{}
+		}
+	case "setMyCommands":
+		f.menus[key] = req.Commands
+	case "deleteMyCommands":
+		delete(f.menus, key)
+	}
+
+	data, _ := json.Marshal(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"ok":true,"result":` + string(data) + `}`))
+}
+
+func (f *fakeCommandMenus) writes() []string {
+	var writes []string
+	for _, call := range f.calls {
+		if call != "getMyCommands" {
+			writes = append(writes, call)
+		}
+	}
+	return writes
+}
+
+func TestBot_SyncCommands(t *testing.T) {
+	noop := func(context.Context, *Bot, *Message) {}
+
+	registry := NewCommandRegistry()
+	registry.Register("start", noop,
+		WithDescription("Iniciar el bot"),
+		WithLocalizedDescription("en", "Start the bot"),
+	)
+	registry.Register("help", noop, WithDescription("Mostrar ayuda"))
+	registry.Register("ban", noop,
+		WithDescription("Expulsar a un usuario"),
+		WithScope(ScopeAllChatAdministrators(), ScopeChat(-100)),
+	)
+	// AdminOnly sin WithScope: solo en el menú de los administradores
+	registry.Register("kick", noop, WithDescription("Sacar a un usuario"), AdminOnly())
+	registry.Register("debug", noop) // Sin descripción: no aparece en el menú
+
+	fake := &fakeCommandMenus{menus: map[menuKey][]BotCommand{
+		{scope: ScopeDefault()}:       {{Command: "old", Description: "Comando viejo"}},
+		{scope: ScopeAllGroupChats()}: {{Command: "stale", Description: "Ya no existe"}},
+		// Ya coincide con el registro, no debe reescribirse
+		{scope: ScopeChat(-100)}: {{Command: "ban", Description: "Expulsar a un usuario"}},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	bot := &Bot{
+		token:           "test-token",
+		client:          &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL:      server.URL + "/bot%s/%s",
+		logger:          testLogger(),
+		commandRegistry: registry,
+	}
+
+	if err := bot.SyncCommands(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[menuKey][]BotCommand{
+		{scope: ScopeDefault()}: {
+			{Command: "start", Description: "Iniciar el bot"},
+			{Command: "help", Description: "Mostrar ayuda"},
+		},
+		{scope: ScopeDefault(), languageCode: "en"}: {
+			{Command: "start", Description: "Start the bot"},
+			{Command: "help", Description: "Mostrar ayuda"},
+		},
+		{scope: ScopeAllChatAdministrators()}: {
+			{Command: "ban", Description: "Expulsar a un usuario"},
+			{Command: "kick", Description: "Sacar a un usuario"},
+		},
+		{scope: ScopeChat(-100)}: {{Command: "ban", Description: "Expulsar a un usuario"}},
+	}
+	if !reflect.DeepEqual(fake.menus, want) {
+		t.Errorf("unexpected menus after sync:\nwant: %v\ngot:  %v", want, fake.menus)
+	}
+
+	wantWrites := []string{"setMyCommands", "deleteMyCommands", "setMyCommands", "setMyCommands"}
+	if got := fake.writes(); !reflect.DeepEqual(got, wantWrites) {
+		t.Errorf("expected writes %v, got %v", wantWrites, got)
+	}
+
+	// Una segunda sincronización no debe modificar nada
+	fake.calls = nil
+	if err := bot.SyncCommands(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if writes := fake.writes(); len(writes) != 0 {
+		t.Errorf("expected no writes on second sync, got %v", writes)
+	}
+}
+
+func TestBot_SyncCommands_NoRegistry(t *testing.T) {
+	bot := NewBot("test-token", WithLogger(testLogger()))
+
+	if err := bot.SyncCommands(context.Background()); err == nil {
+		t.Error("expected error without command registry, got nil")
+	}
+}
+
+func TestCommandRegistry_Register_KeepsOrder(t *testing.T) {
+	registry := NewCommandRegistry()
+	noop := func(context.Context, *Bot, *Message) {}
+
+	registry.Register("b", noop, WithDescription("B"))
+	registry.Register("a", noop, WithDescription("A"))
+	registry.Register("b", noop, WithDescription("B2")) // Sobrescribe sin cambiar la posición
+
+	got := registry.menus()[menuKey{scope: ScopeDefault()}]
+	want := []BotCommand{{Command: "b", Description: "B2"}, {Command: "a", Description: "A"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
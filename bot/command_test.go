@@ -2,9 +2,25 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// commandMessage construye un mensaje de texto con la entidad bot_command que
+// Telegram agrega cuando el texto comienza con un comando.
+func commandMessage(text string) *Message {
+	msg := &Message{Text: text, Chat: &Chat{ID: 123}}
+
+	command, _, _ := strings.Cut(text, " ")
+	if len(command) > 1 && strings.HasPrefix(command, "/") {
+		msg.Entities = []MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+
+	return msg
+}
+
 func TestNewCommandRegistry(t *testing.T) {
 	registry := NewCommandRegistry()
 
@@ -83,7 +99,7 @@ func TestCommandRegistry_Register_Overwrite(t *testing.T) {
 	}
 
 	// Verify the second handler is registered
-	msg := &Message{Text: "/start", Chat: &Chat{ID: 123}}
+	msg := commandMessage("/start")
 	ctx := context.Background()
 	bot := NewBot("test-token")
 	registry.Execute(ctx, bot, msg)
@@ -181,10 +197,7 @@ func TestCommandRegistry_Execute(t *testing.T) {
 				registry.Register(tt.command, handler)
 			}
 
-			msg := &Message{
-				Text: tt.msgText,
-				Chat: &Chat{ID: 123},
-			}
+			msg := commandMessage(tt.msgText)
 
 			bot := NewBot("test-token")
 			ctx := context.Background()
@@ -256,10 +269,7 @@ func TestCommandRegistry_Execute_CommandExtraction(t *testing.T) {
 
 			registry.Register(tt.expectedCmd, handler)
 
-			msg := &Message{
-				Text: tt.msgText,
-				Chat: &Chat{ID: 123},
-			}
+			msg := commandMessage(tt.msgText)
 
 			bot := NewBot("test-token")
 			ctx := context.Background()
@@ -299,10 +309,7 @@ func TestCommandRegistry_Execute_Concurrent(t *testing.T) {
 	done := make(chan bool, 10)
 	for i := 0; i < 10; i++ {
 		go func() {
-			msg := &Message{
-				Text: "/start",
-				Chat: &Chat{ID: 123},
-			}
+			msg := commandMessage("/start")
 			registry.Execute(ctx, bot, msg)
 			done <- true
 		}()
@@ -318,3 +325,176 @@ func TestCommandRegistry_Execute_Concurrent(t *testing.T) {
 	}
 }
 
+func TestMessage_Command(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want *ParsedCommand
+	}{
+		{
+			name: "command with mention and args",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"group"},"date":1,"text":"/remind@equipo_bot 10m revisar PR","entities":[{"type":"bot_command","offset":0,"length":18}]}`,
+			want: &ParsedCommand{Name: "remind", Mention: "equipo_bot", Args: "10m revisar PR"},
+		},
+		{
+			name: "multiline args",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"text":"/note\nprimera línea\nsegunda","entities":[{"type":"bot_command","offset":0,"length":5}]}`,
+			want: &ParsedCommand{Name: "note", Args: "primera línea\nsegunda"},
+		},
+		{
+			name: "args with emoji",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"text":"/react 👍🏽 genial","entities":[{"type":"bot_command","offset":0,"length":6}]}`,
+			want: &ParsedCommand{Name: "react", Args: "👍🏽 genial"},
+		},
+		{
+			name: "photo caption",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"photo":[{"file_id":"p","file_unique_id":"u","width":90,"height":90}],"caption":"/scan factura","caption_entities":[{"type":"bot_command","offset":0,"length":5}]}`,
+			want: &ParsedCommand{Name: "scan", Args: "factura"},
+		},
+		{
+			name: "path without command entity",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"text":"/usr/bin/env no existe"}`,
+			want: nil,
+		},
+		{
+			name: "command not at the start",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"text":"🤖 usá /start","entities":[{"type":"bot_command","offset":7,"length":6}]}`,
+			want: nil,
+		},
+		{
+			name: "plain text",
+			raw:  `{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"text":"hola"}`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg Message
+			if err := json.Unmarshal([]byte(tt.raw), &msg); err != nil {
+				t.Fatalf("error unmarshaling message: %v", err)
+			}
+
+			got := msg.Command()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUTF16Index(t *testing.T) {
+	// "🤖" ocupa 2 unidades UTF-16 y 4 bytes; "á" ocupa 1 unidad y 2 bytes
+	text := "🤖 usá /start"
+
+	tests := []struct {
+		units int
+		want  string
+	}{
+		{units: 0, want: "🤖 usá /start"},
+		{units: 2, want: " usá /start"},
+		{units: 6, want: " /start"},
+		{units: 7, want: "/start"},
+		{units: 100, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := text[utf16Index(text, tt.units):]; got != tt.want {
+			t.Errorf("utf16Index(%d): expected %q, got %q", tt.units, tt.want, got)
+		}
+	}
+}
+
+func TestCommandRegistry_Execute_Caption(t *testing.T) {
+	registry := NewCommandRegistry()
+
+	var received string
+	registry.Register("scan", func(ctx context.Context, bot *Bot, msg *Message) {
+		received = msg.Command().Args
+	})
+
+	msg := &Message{
+		Chat:            &Chat{ID: 123},
+		Photo:           []PhotoSize{{FileID: "p", FileUniqueID: "u", Width: 90, Height: 90}},
+		Caption:         "/scan factura",
+		CaptionEntities: []MessageEntity{{Type: "bot_command", Offset: 0, Length: 5}},
+	}
+
+	if !registry.Execute(context.Background(), NewBot("test-token"), msg) {
+		t.Fatal("expected caption command to be executed")
+	}
+
+	if received != "factura" {
+		t.Errorf("expected args %q, got %q", "factura", received)
+	}
+}
+
+func TestCommandRegistry_Execute_Mention(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		fold         bool
+		msgText      string
+		wantExecuted bool
+	}{
+		{
+			name:         "unaddressed command",
+			username:     "equipo_bot",
+			msgText:      "/start",
+			wantExecuted: true,
+		},
+		{
+			name:         "addressed to this bot",
+			username:     "equipo_bot",
+			msgText:      "/start@equipo_bot",
+			wantExecuted: true,
+		},
+		{
+			name:         "addressed to another bot",
+			username:     "equipo_bot",
+			msgText:      "/start@OtherBot",
+			wantExecuted: false,
+		},
+		{
+			name:         "different case is another bot by default",
+			username:     "Equipo_Bot",
+			msgText:      "/start@equipo_bot",
+			wantExecuted: false,
+		},
+		{
+			name:         "different case with case-insensitive mentions",
+			username:     "Equipo_Bot",
+			fold:         true,
+			msgText:      "/start@equipo_bot",
+			wantExecuted: true,
+		},
+		{
+			name:         "unknown username accepts any mention",
+			username:     "",
+			msgText:      "/start@OtherBot",
+			wantExecuted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewCommandRegistry()
+			registry.Register("start", func(ctx context.Context, bot *Bot, msg *Message) {})
+
+			var opts []BotOption
+			if tt.fold {
+				opts = append(opts, WithCaseInsensitiveMentions())
+			}
+
+			bot := NewBot("test-token", opts...)
+			if tt.username != "" {
+				bot.me.Store(&User{ID: 999, IsBot: true, FirstName: "Equipo", Username: tt.username})
+			}
+
+			executed := registry.Execute(context.Background(), bot, commandMessage(tt.msgText))
+			if executed != tt.wantExecuted {
+				t.Errorf("expected executed=%v, got %v", tt.wantExecuted, executed)
+			}
+		})
+	}
+}
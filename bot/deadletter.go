@@ -0,0 +1,217 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Espera entre los intentos de guardar una dead letter: se duplica en cada
+// falla, sin depender de AckPolicy.RetryDelay, para no saturar un sink caído.
+const (
+	deadLetterMinDelay = 500 * time.Millisecond
+	deadLetterMaxDelay = 30 * time.Second
+)
+
+// DeadLetter es una actualización que no se pudo procesar: agotó los
+// intentos de WithAckMode o, sin modo ack, falló en su único intento.
+type DeadLetter struct {
+	Update *Update `json:"-"`
+	// Raw es el JSON de la actualización tal como lo envió Telegram, incluidos
+	// los campos que Update no modela.
+	Raw      json.RawMessage `json:"update"`
+	Error    string          `json:"error"`
+	Handler  string          `json:"handler"` // "/comando" o el tipo de actualización
+	Attempts int             `json:"attempts"`
+}
+
+// DeadLetterSink recibe las actualizaciones que fallaron. Si Put retorna
+// error se reintenta y, en modo ack, la actualización no se confirma hasta
+// que se guarde.
+type DeadLetterSink interface {
+	Put(ctx context.Context, letter DeadLetter) error
+}
+
+// WithDeadLetterSink configura dónde se guardan las actualizaciones que
+// fallaron en Start o Replay. En modo ack se guardan al agotar los
+// intentos; sin modo ack, las que fallan o entran en pánico en su único
+// intento. Sin sink, solo se registran en el log.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token,
+//	    bot.WithAckMode(bot.DefaultAckPolicy()),
+//	    bot.WithDeadLetterSink(bot.NewFileDeadLetterSink("data/dead-letters.jsonl")),
+//	)
+func WithDeadLetterSink(sink DeadLetterSink) BotOption {
+	return func(b *Bot) {
+		b.deadLetters = sink
+	}
+}
+
+// FileDeadLetterSink guarda las dead letters en un archivo JSONL, una por
+// línea, que se puede volver a procesar con Replay.
+type FileDeadLetterSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileDeadLetterSink crea un DeadLetterSink que agrega las dead letters al
+// final de path. El directorio debe existir; el archivo se crea en el primer
+// Put.
+func NewFileDeadLetterSink(path string) *FileDeadLetterSink {
+	return &FileDeadLetterSink{path: path}
+}
+
+func (s *FileDeadLetterSink) Put(ctx context.Context, letter DeadLetter) error {
+	if letter.Raw == nil && letter.Update != nil {
+		letter.Raw = letter.Update.rawJSON()
+	}
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("error marshaling dead letter: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening dead letter file: %w", err)
+	}
+
+	// La línea se escribe de una vez para no dejar líneas mezcladas
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing dead letter file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("error syncing dead letter file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error closing dead letter file: %w", err)
+	}
+
+	return nil
+}
+
+// Replay vuelve a procesar las dead letters leídas de r, en el formato de
+// FileDeadLetterSink, con el mismo dispatcher y los mismos handlers que
+// Start. Sirve para reprocesar las actualizaciones después de desplegar una
+// corrección. Retorna cuando terminaron todas o al cancelarse ctx.
+//
+// Las que vuelven a fallar se envían de nuevo al DeadLetterSink (en modo ack,
+// después de reintentarlas), por lo que r no debe ser el archivo donde
+// escribe el sink. Sin sink solo se registran en el log.
+//
+// Ejemplo:
+//
+//	os.Rename("data/dead-letters.jsonl", "data/replay.jsonl")
+//	f, err := os.Open("data/replay.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//	if err := b.Replay(ctx, f); err != nil {
+//	    log.Fatal(err)
+//	}
+func (b *Bot) Replay(ctx context.Context, r io.Reader) error {
+	if b.deadLetters == nil {
+		b.logger.Warn("Replay sin DeadLetterSink: las actualizaciones que vuelvan a fallar solo se registran en el log")
+	}
+
+	d := newDispatcher(b.dispatch, func(update *Update) bool {
+		return b.processUpdate(ctx, update)
+	})
+
+	decoder := json.NewDecoder(r)
+	replayed := 0
+
+	var err error
+	for {
+		var letter DeadLetter
+		if err = decoder.Decode(&letter); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			} else {
+				err = fmt.Errorf("error decoding dead letter: %w", err)
+			}
+			break
+		}
+		if len(letter.Raw) == 0 || string(letter.Raw) == "null" {
+			err = errors.New("error decoding dead letter: missing update")
+			break
+		}
+		// Se decodifica desde el JSON original para que, si vuelve a fallar,
+		// la dead letter conserve los campos que Update no modela
+		var update *Update
+		if update, err = decodeUpdate(letter.Raw); err != nil {
+			err = fmt.Errorf("error decoding dead letter: %w", err)
+			break
+		}
+
+		if err = d.submit(ctx, update); err != nil {
+			break
+		}
+		replayed++
+	}
+
+	d.wait()
+
+	b.logger.Info("Dead letters reprocesadas", slog.Int("count", replayed))
+	return err
+}
+
+// handlerName identifica el handler que procesa la actualización: el comando
+// registrado para los comandos, o el tipo de actualización para el resto.
+func (b *Bot) handlerName(update *Update) string {
+	if update.Message != nil && b.commandRegistry != nil {
+		if cmd := update.Message.Command(); cmd != nil {
+			if _, ok := b.commandRegistry.registry[cmd.Name]; ok {
+				return "/" + cmd.Name
+			}
+		}
+	}
+	return update.Type()
+}
+
+// putDeadLetter guarda la actualización fallida en el sink configurado,
+// reintentando con backoff exponencial mientras falle. Retorna false si el
+// contexto se cancela antes de poder guardarla.
+func (b *Bot) putDeadLetter(ctx context.Context, letter DeadLetter) bool {
+	b.logger.Error("Update descartado después de agotar los intentos",
+		append(updateAttrs(letter.Update),
+			slog.String("handler", letter.Handler),
+			slog.Int("attempts", letter.Attempts),
+			slog.String("error", letter.Error),
+		)...,
+	)
+
+	if b.deadLetters == nil {
+		return true
+	}
+
+	delay := deadLetterMinDelay
+	for {
+		err := b.deadLetters.Put(ctx, letter)
+		if err == nil {
+			return true
+		}
+
+		b.logger.Error("Error guardando dead letter",
+			slog.Int("update_id", letter.Update.UpdateID),
+			slog.String("error", err.Error()),
+		)
+		if b.clock.Sleep(ctx, delay) != nil {
+			return false
+		}
+		delay = min(delay*2, deadLetterMaxDelay)
+	}
+}
@@ -0,0 +1,366 @@
+package bot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileDeadLetterSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+	sink := NewFileDeadLetterSink(path)
+
+	letters := []DeadLetter{
+		{Update: messageUpdate(1), Error: "boom", Handler: "message", Attempts: 3},
+		{Update: messageUpdate(2), Error: "handler panic: boom", Handler: "/pay", Attempts: 1},
+	}
+	for _, letter := range letters {
+		if err := sink.Put(context.Background(), letter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var got []DeadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			t.Fatalf("expected one dead letter per line, got %q: %v", scanner.Text(), err)
+		}
+		got = append(got, letter)
+	}
+
+	if len(got) != len(letters) {
+		t.Fatalf("expected %d dead letters, got %d", len(letters), len(got))
+	}
+	for i, letter := range got {
+		want := letters[i]
+		update, err := decodeUpdate(letter.Raw)
+		if err != nil {
+			t.Fatalf("line %d: invalid update %s: %v", i+1, letter.Raw, err)
+		}
+		if update.UpdateID != want.Update.UpdateID || update.Message.Text != want.Update.Message.Text ||
+			letter.Error != want.Error || letter.Handler != want.Handler || letter.Attempts != want.Attempts {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, want, letter)
+		}
+	}
+}
+
+func TestFileDeadLetterSink_MissingDirectory(t *testing.T) {
+	sink := NewFileDeadLetterSink(filepath.Join(t.TempDir(), "missing", "dead-letters.jsonl"))
+
+	if err := sink.Put(context.Background(), DeadLetter{Update: messageUpdate(1)}); err == nil {
+		t.Error("expected error writing to a missing directory")
+	}
+}
+
+func TestBot_Replay(t *testing.T) {
+	line := func(id int, text string) string {
+		update := messageUpdate(id)
+		update.Message.Chat.ID = int64(id)
+		update.Message.Text = text
+		data, _ := json.Marshal(DeadLetter{Raw: update.rawJSON(), Error: "boom", Handler: "message", Attempts: 3})
+		return string(data) + "\n"
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantTexts []string
+		wantErr   bool
+	}{
+		{
+			name:      "replays every dead letter",
+			input:     line(1, "uno") + line(2, "dos") + line(3, "tres"),
+			wantTexts: []string{"dos", "tres", "uno"},
+		},
+		{
+			name:  "empty input",
+			input: "",
+		},
+		{
+			name:      "invalid line stops the replay",
+			input:     line(1, "uno") + "not json\n" + line(2, "dos"),
+			wantTexts: []string{"uno"},
+			wantErr:   true,
+		},
+		{
+			name:    "dead letter without update",
+			input:   `{"error":"boom","attempts":1}` + "\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var texts []string
+			bot := NewBot("test-token",
+				WithLogger(testLogger()),
+				OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+					mu.Lock()
+					texts = append(texts, msg.Text)
+					mu.Unlock()
+					return nil
+				}),
+			)
+
+			err := bot.Replay(context.Background(), strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			slices.Sort(texts)
+			if !slices.Equal(texts, tt.wantTexts) {
+				t.Errorf("expected handled messages %v, got %v", tt.wantTexts, texts)
+			}
+		})
+	}
+}
+
+func TestBot_Replay_FailsAgain(t *testing.T) {
+	// Un comando que falla queda en el archivo con su nombre; al reprocesarlo
+	// sin la corrección vuelve al sink
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	var calls int
+	registry := NewCommandRegistry()
+	registry.Handle("pay", func(ctx context.Context, b *Bot, msg *Message) error {
+		calls++
+		return errors.New("payment failed")
+	})
+
+	update := messageUpdate(5)
+	update.Message.Text = "/pay"
+	update.Message.Entities = []MessageEntity{{Type: "bot_command", Offset: 0, Length: 4}}
+
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithCommandRegistry(registry),
+		WithAckMode(AckPolicy{MaxAttempts: 1}),
+		WithDeadLetterSink(NewFileDeadLetterSink(path)),
+	)
+	if !bot.processUpdate(context.Background(), update) {
+		t.Fatal("expected update to be acknowledged after reaching the sink")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	sink := &memoryDeadLetterSink{}
+	bot.deadLetters = sink
+	if err := bot.Replay(context.Background(), f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected the command to run once more on replay, got %d calls", calls)
+	}
+	if len(sink.letters) != 1 {
+		t.Fatalf("expected the replayed update to fail again, got %d dead letters", len(sink.letters))
+	}
+	letter := sink.letters[0]
+	if letter.Update.UpdateID != 5 || letter.Handler != "/pay" || letter.Error != "payment failed" || letter.Attempts != 1 {
+		t.Errorf("unexpected dead letter: %+v", letter)
+	}
+}
+
+// diceUpdate incluye campos que Update no modela y que deben llegar intactos
+// a las dead letters.
+const diceUpdate = `{"update_id":9,"message":{"message_id":9,"date":1,"chat":{"id":1,"type":"private"},"from":{"id":1,"first_name":"A"},"dice":{"emoji":"🎲","value":6},"venue":{"title":"Bar","address":"Calle 1"}}}`
+
+func TestBot_RawUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		receive func(t *testing.T, b *Bot) *Update
+	}{
+		{
+			name: "polling",
+			receive: func(t *testing.T, b *Bot) *Update {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"ok":true,"result":[` + diceUpdate + `]}`))
+				}))
+				defer server.Close()
+				b.apiBaseURL = server.URL + "/bot%s/%s"
+
+				updates, err := b.pollUpdates(context.Background(), 0)
+				if err != nil || len(updates) != 1 {
+					t.Fatalf("expected 1 update, got %d: %v", len(updates), err)
+				}
+				return &updates[0]
+			},
+		},
+		{
+			name: "webhook",
+			receive: func(t *testing.T, b *Bot) *Update {
+				var received *Update
+				b.middlewares = append(b.middlewares, func(next Handler) Handler {
+					return func(ctx context.Context, b *Bot, update *Update) error {
+						received = update
+						return next(ctx, b, update)
+					}
+				})
+
+				rec := httptest.NewRecorder()
+				b.WebhookHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(diceUpdate)))
+				if received == nil {
+					t.Fatalf("expected the update to be handled, got status %d", rec.Code)
+				}
+				return received
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memoryDeadLetterSink{}
+			bot := NewBot("test-token",
+				WithLogger(testLogger()),
+				WithAckMode(AckPolicy{MaxAttempts: 1}),
+				WithDeadLetterSink(sink),
+				OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+					return errors.New("boom")
+				}),
+			)
+
+			update := tt.receive(t, bot)
+			bot.processUpdate(context.Background(), update)
+
+			if len(sink.letters) != 1 {
+				t.Fatalf("expected 1 dead letter, got %d", len(sink.letters))
+			}
+			if got := string(sink.letters[0].Raw); got != diceUpdate {
+				t.Errorf("expected the raw update %s, got %s", diceUpdate, got)
+			}
+		})
+	}
+}
+
+func TestBot_Replay_KeepsRawUpdate(t *testing.T) {
+	// Una dead letter que vuelve a fallar se guarda con el JSON original,
+	// no con el de los campos que modela Update
+	dir := t.TempDir()
+	input := `{"update":` + diceUpdate + `,"error":"boom","handler":"message","attempts":1}` + "\n"
+	path := filepath.Join(dir, "dead-letters.jsonl")
+
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithAckMode(AckPolicy{MaxAttempts: 1}),
+		WithDeadLetterSink(NewFileDeadLetterSink(path)),
+		OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+			return errors.New("boom")
+		}),
+	)
+	if err := bot.Replay(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		t.Fatalf("invalid dead letter %q: %v", data, err)
+	}
+	if got := string(letter.Raw); got != diceUpdate {
+		t.Errorf("expected the raw update %s, got %s", diceUpdate, got)
+	}
+}
+
+func TestBot_processUpdate_DeadLetterWithoutAckMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   func(ctx context.Context, b *Bot, msg *Message) error
+		wantError string // Error de la dead letter; vacío si no debe haber una
+	}{
+		{
+			name:    "succeeds",
+			handler: func(ctx context.Context, b *Bot, msg *Message) error { return nil },
+		},
+		{
+			name:      "error",
+			handler:   func(ctx context.Context, b *Bot, msg *Message) error { return errors.New("boom") },
+			wantError: "boom",
+		},
+		{
+			name:      "panic is recovered",
+			handler:   func(ctx context.Context, b *Bot, msg *Message) error { panic("boom") },
+			wantError: "handler panic: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			sink := &memoryDeadLetterSink{}
+			bot := NewBot("test-token",
+				WithLogger(testLogger()),
+				WithDeadLetterSink(sink),
+				OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+					calls++
+					return tt.handler(ctx, b, msg)
+				}),
+			)
+
+			if !bot.processUpdate(context.Background(), messageUpdate(1)) {
+				t.Fatal("expected update to be acknowledged without ack mode")
+			}
+			if calls != 1 {
+				t.Errorf("expected a single attempt without ack mode, got %d", calls)
+			}
+
+			if tt.wantError == "" {
+				if len(sink.letters) != 0 {
+					t.Errorf("expected no dead letters, got %+v", sink.letters)
+				}
+				return
+			}
+			if len(sink.letters) != 1 {
+				t.Fatalf("expected 1 dead letter, got %d", len(sink.letters))
+			}
+			if letter := sink.letters[0]; letter.Error != tt.wantError || letter.Attempts != 1 || letter.Handler != "message" {
+				t.Errorf("unexpected dead letter: %+v", letter)
+			}
+		})
+	}
+}
+
+func TestBot_Replay_WithoutAckMode(t *testing.T) {
+	// Sin modo ack, lo que vuelve a fallar durante el replay no se pierde
+	sink := &memoryDeadLetterSink{}
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithDeadLetterSink(sink),
+		OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+			return errors.New("boom")
+		}),
+	)
+
+	input := `{"update":` + diceUpdate + `,"error":"boom","handler":"message","attempts":3}` + "\n"
+	if err := bot.Replay(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sink.letters) != 1 || string(sink.letters[0].Raw) != diceUpdate {
+		t.Errorf("expected the update to reach the sink again, got %+v", sink.letters)
+	}
+}
@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"context"
+	"sync"
+)
+
+// DispatchConfig define cómo Start reparte las actualizaciones entre los
+// handlers.
+type DispatchConfig struct {
+	// Workers es la cantidad máxima de actualizaciones procesadas en paralelo.
+	Workers int
+	// QueueSize es la cantidad de actualizaciones que pueden esperar un worker
+	// libre. Con la cola llena Start deja de pedir actualizaciones a Telegram
+	// hasta que se libere lugar.
+	QueueSize int
+	// OrderKey agrupa las actualizaciones que deben procesarse en orden, de a
+	// una por vez. Las que tienen distinta clave se procesan en paralelo, y las
+	// de clave 0 no tienen orden. Por defecto es OrderByChat.
+	OrderKey func(*Update) int64
+}
+
+// DefaultDispatchConfig retorna la configuración por defecto: 10 workers, una
+// cola de 100 actualizaciones (un lote completo de getUpdates) y orden por
+// chat.
+func DefaultDispatchConfig() DispatchConfig {
+	return DispatchConfig{
+		Workers:   10,
+		QueueSize: 100,
+		OrderKey:  OrderByChat,
+	}
+}
+
+// WithDispatcher configura el procesamiento de las actualizaciones recibidas
+// por Start. Los campos en cero toman el valor de DefaultDispatchConfig.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithDispatcher(bot.DispatchConfig{
+//	    Workers:   4,
+//	    QueueSize: 50,
+//	    OrderKey:  bot.OrderByUser,
+//	}))
+func WithDispatcher(config DispatchConfig) BotOption {
+	return func(b *Bot) {
+		b.dispatch = config
+	}
+}
+
+// OrderByChat procesa en orden las actualizaciones de cada chat. Las que no
+// pertenecen a un chat, como las inline queries, se ordenan por usuario.
+func OrderByChat(update *Update) int64 {
+	if chat := update.Chat(); chat != nil {
+		return chat.ID
+	}
+	return OrderByUser(update)
+}
+
+// OrderByUser procesa en orden las actualizaciones de cada usuario, aunque
+// provengan de chats distintos.
+func OrderByUser(update *Update) int64 {
+	if from := update.From(); from != nil {
+		return from.ID
+	}
+	return 0
+}
+
+// dispatcher procesa las actualizaciones con una cantidad fija de workers.
+// Las actualizaciones con la misma clave esperan en una cola propia, de modo
+// que nunca se procesan dos a la vez ni fuera de orden.
+type dispatcher struct {
+	// handle retorna false si la actualización no quedó confirmada.
+	handle func(*Update) bool
+	key    func(*Update) int64
+
+	// slots limita las actualizaciones pendientes: submit se bloquea cuando
+	// está lleno.
+	slots chan struct{}
+	// ready contiene las colas con actualizaciones listas para procesar. Su
+	// capacidad es la de slots, ya que nunca hay más colas que actualizaciones
+	// pendientes, por lo que enviar a ready no se bloquea.
+	ready chan *keyQueue
+	// progress recibe una señal cada vez que se confirma una actualización.
+	progress chan struct{}
+
+	mu         sync.Mutex
+	queues     map[int64]*keyQueue
+	unfinished map[int]bool // IDs de las actualizaciones sin confirmar
+	aborted    bool
+	pending    sync.WaitGroup
+	workers    sync.WaitGroup
+}
+
+// keyQueue son las actualizaciones pendientes de una misma clave.
+type keyQueue struct {
+	key     int64
+	updates []*Update
+}
+
+func newDispatcher(config DispatchConfig, handle func(*Update) bool) *dispatcher {
+	defaults := DefaultDispatchConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.OrderKey == nil {
+		config.OrderKey = defaults.OrderKey
+	}
+
+	capacity := config.Workers + config.QueueSize
+	d := &dispatcher{
+		handle:     handle,
+		key:        config.OrderKey,
+		slots:      make(chan struct{}, capacity),
+		ready:      make(chan *keyQueue, capacity),
+		progress:   make(chan struct{}, 1),
+		queues:     make(map[int64]*keyQueue),
+		unfinished: make(map[int]bool),
+	}
+
+	d.workers.Add(config.Workers)
+	for range config.Workers {
+		go d.work()
+	}
+
+	return d
+}
+
+// submit encola la actualización. Si la cola está llena espera a que se
+// libere lugar o a que se cancele el contexto.
+func (d *dispatcher) submit(ctx context.Context, update *Update) error {
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	d.pending.Add(1)
+
+	key := d.key(update)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unfinished[update.UpdateID] = true
+
+	// Si la clave ya tiene una cola, un worker la está procesando o ya está
+	// en ready: basta con agregar la actualización al final
+	if q, ok := d.queues[key]; ok && key != 0 {
+		q.updates = append(q.updates, update)
+		return nil
+	}
+
+	q := &keyQueue{key: key, updates: []*Update{update}}
+	if key != 0 {
+		d.queues[key] = q
+	}
+	d.ready <- q
+	return nil
+}
+
+// work procesa una actualización por vez. Después de cada una, la cola
+// vuelve al final de ready si le quedan actualizaciones, para que un chat con
+// mucha actividad no acapare un worker.
+func (d *dispatcher) work() {
+	defer d.workers.Done()
+
+	for q := range d.ready {
+		d.mu.Lock()
+		update := q.updates[0]
+		aborted := d.aborted
+		d.mu.Unlock()
+
+		// Después de abort las actualizaciones en cola se descartan sin
+		// procesar y quedan sin confirmar
+		acked := !aborted && d.handle(update)
+
+		d.mu.Lock()
+		if acked {
+			delete(d.unfinished, update.UpdateID)
+		}
+		q.updates = q.updates[1:]
+		if len(q.updates) > 0 {
+			d.ready <- q
+		} else if q.key != 0 {
+			delete(d.queues, q.key)
+		}
+		d.mu.Unlock()
+
+		<-d.slots
+		d.pending.Done()
+
+		if acked {
+			select {
+			case d.progress <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// waitProgress espera a que se confirme alguna actualización o a que se
+// cancele el contexto.
+func (d *dispatcher) waitProgress(ctx context.Context) {
+	select {
+	case <-d.progress:
+	case <-ctx.Done():
+	}
+}
+
+// abort descarta las actualizaciones que todavía están en cola. Las que ya
+// están en proceso continúan.
+func (d *dispatcher) abort() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.aborted = true
+}
+
+// firstUnfinished retorna el menor ID de las actualizaciones encoladas, en
+// proceso o que no se confirmaron. Telegram debe volver a entregar las
+// actualizaciones a partir de ese ID.
+func (d *dispatcher) firstUnfinished() (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	first, found := 0, false
+	for id := range d.unfinished {
+		if !found || id < first {
+			first, found = id, true
+		}
+	}
+	return first, found
+}
+
+// wait espera a que se procesen todas las actualizaciones encoladas y
+// detiene los workers. No se debe llamar a submit después de wait.
+func (d *dispatcher) wait() {
+	d.pending.Wait()
+	close(d.ready)
+	d.workers.Wait()
+}
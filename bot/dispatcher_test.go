@@ -0,0 +1,205 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// chatUpdate arma un update de mensaje en el chat indicado.
+func chatUpdate(id int, chatID int64) *Update {
+	return &Update{UpdateID: id, Message: &Message{MessageID: id, Chat: &Chat{ID: chatID}}}
+}
+
+func TestDispatcher_PerChatOrder(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		order  = make(map[int64][]int)
+		active = make(map[int64]int)
+	)
+
+	d := newDispatcher(DispatchConfig{Workers: 4, QueueSize: 10}, func(update *Update) bool {
+		chatID := update.Message.Chat.ID
+
+		mu.Lock()
+		active[chatID]++
+		if active[chatID] > 1 {
+			t.Errorf("chat %d processed concurrently", chatID)
+		}
+		mu.Unlock()
+
+		// Duraciones distintas para que un procesamiento paralelo desordene los updates
+		time.Sleep(time.Duration(update.UpdateID%3) * time.Millisecond)
+
+		mu.Lock()
+		active[chatID]--
+		order[chatID] = append(order[chatID], update.UpdateID)
+		mu.Unlock()
+		return true
+	})
+
+	for id := 1; id <= 60; id++ {
+		if err := d.submit(context.Background(), chatUpdate(id, int64(id%3+1))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	d.wait()
+
+	for chatID, ids := range order {
+		if len(ids) != 20 {
+			t.Errorf("chat %d: expected 20 updates, got %d", chatID, len(ids))
+		}
+		if !slices.IsSorted(ids) {
+			t.Errorf("chat %d: updates processed out of order: %v", chatID, ids)
+		}
+	}
+}
+
+func TestDispatcher_ConcurrencyCap(t *testing.T) {
+	const workers = 3
+
+	var active, maxActive atomic.Int32
+	d := newDispatcher(DispatchConfig{Workers: workers, QueueSize: 20}, func(update *Update) bool {
+		n := active.Add(1)
+		for {
+			current := maxActive.Load()
+			if n <= current || maxActive.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		active.Add(-1)
+		return true
+	})
+
+	// Cada update en un chat distinto: solo el pool limita el paralelismo
+	for id := 1; id <= 20; id++ {
+		if err := d.submit(context.Background(), chatUpdate(id, int64(id))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	d.wait()
+
+	if got := maxActive.Load(); got != workers {
+		t.Errorf("expected at most %d concurrent handlers, got %d", workers, got)
+	}
+}
+
+func TestDispatcher_UnorderedKey(t *testing.T) {
+	release := make(chan struct{})
+	var started atomic.Int32
+
+	d := newDispatcher(DispatchConfig{Workers: 2, QueueSize: 2}, func(update *Update) bool {
+		started.Add(1)
+		<-release
+		return true
+	})
+
+	// Sin chat ni usuario la clave es 0 y los updates no se serializan
+	d.submit(context.Background(), &Update{UpdateID: 1, Poll: &Poll{ID: "a"}})
+	d.submit(context.Background(), &Update{UpdateID: 2, Poll: &Poll{ID: "b"}})
+
+	deadline := time.Now().Add(time.Second)
+	for started.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	d.wait()
+
+	if started.Load() != 2 {
+		t.Errorf("expected unordered updates to run in parallel, started %d", started.Load())
+	}
+}
+
+func TestDispatcher_Backpressure(t *testing.T) {
+	release := make(chan struct{})
+	d := newDispatcher(DispatchConfig{Workers: 1, QueueSize: 1}, func(update *Update) bool {
+		<-release
+		return true
+	})
+
+	// Uno en proceso y uno en cola llenan el dispatcher
+	for id := 1; id <= 2; id++ {
+		if err := d.submit(context.Background(), chatUpdate(id, int64(id))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.submit(ctx, chatUpdate(3, 3)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected submit to block until the deadline, got %v", err)
+	}
+
+	close(release)
+	if err := d.submit(context.Background(), chatUpdate(4, 4)); err != nil {
+		t.Errorf("expected submit to succeed after the queue drains, got %v", err)
+	}
+	d.wait()
+}
+
+func TestBot_Start_PausesPollingWhenQueueIsFull(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.HasSuffix(r.URL.Path, "/getMe") {
+			w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Bot","username":"testbot"}}`))
+			return
+		}
+
+		n := polls.Add(1)
+		if n > 1 {
+			// Lotes siguientes: nunca deberían pedirse mientras la cola está llena
+			w.Write([]byte(`{"ok":true,"result":[]}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":[` +
+			`{"update_id":1,"message":{"message_id":1,"chat":{"id":1},"from":{"id":1,"first_name":"A"},"text":"a"}},` +
+			`{"update_id":2,"message":{"message_id":2,"chat":{"id":2},"from":{"id":2,"first_name":"B"},"text":"b"}},` +
+			`{"update_id":3,"message":{"message_id":3,"chat":{"id":3},"from":{"id":3,"first_name":"C"},"text":"c"}}` +
+			`]}`))
+	}))
+	defer server.Close()
+
+	release := make(chan struct{})
+	var handled atomic.Int32
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithDispatcher(DispatchConfig{Workers: 1, QueueSize: 1}),
+		OnMessage(func(context.Context, *Bot, *Message) error {
+			handled.Add(1)
+			<-release
+			return nil
+		}),
+	)
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Start(ctx) }()
+
+	// El tercer update no entra en la cola, por lo que Start no vuelve a pedir updates
+	time.Sleep(100 * time.Millisecond)
+	if got := polls.Load(); got != 1 {
+		t.Errorf("expected polling to pause with a full queue, got %d getUpdates calls", got)
+	}
+	if got := handled.Load(); got != 1 {
+		t.Errorf("expected one handler running, got %d", got)
+	}
+
+	cancel()
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after cancellation")
+	}
+}
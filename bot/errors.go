@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errores centinela para clasificar las respuestas de error de la API.
+// Se usan con errors.Is sobre el error retornado por cualquier método del bot:
+//
+//	if _, err := b.SendMessage(ctx, chatID, text); errors.Is(err, bot.ErrForbidden) {
+//	    // El usuario bloqueó al bot
+//	}
+var (
+	ErrBadRequest      = errors.New("bad request")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrServerError     = errors.New("server error")
+
+	// ErrChatNotFound indica que el chat no existe o el bot no tiene acceso a él.
+	ErrChatNotFound = errors.New("chat not found")
+	// ErrBotBlocked indica que el usuario bloqueó al bot. También cumple ErrForbidden.
+	ErrBotBlocked = errors.New("bot was blocked by the user")
+	// ErrChatMigrated indica que el grupo fue migrado a un supergrupo;
+	// el nuevo ID está en APIError.Parameters.MigrateToChatID.
+	ErrChatMigrated = errors.New("chat migrated")
+)
+
+// APIError representa una respuesta con ok=false de la API de Telegram.
+//
+// Se puede inspeccionar con errors.As para obtener el código y los
+// parámetros de la respuesta:
+//
+//	var apiErr *bot.APIError
+//	if errors.As(err, &apiErr) && apiErr.Parameters != nil {
+//	    log.Printf("reintentar en %d segundos", apiErr.Parameters.RetryAfter)
+//	}
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	Parameters  *ResponseParameters
+}
+
+func (e *APIError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("API error: %s", e.Description)
+	}
+	return fmt.Sprintf("API error %d: %s", e.Code, e.Description)
+}
+
+// Is permite comparar el error con los errores centinela del paquete.
+func (e *APIError) Is(target error) bool {
+	description := strings.ToLower(e.Description)
+
+	switch target {
+	case ErrBadRequest:
+		return e.Code == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.Code == http.StatusForbidden
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound
+	case ErrConflict:
+		return e.Code == http.StatusConflict
+	case ErrTooManyRequests:
+		return e.Code == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.Code >= http.StatusInternalServerError
+	case ErrChatNotFound:
+		return e.Code == http.StatusBadRequest && strings.Contains(description, "chat not found")
+	case ErrBotBlocked:
+		return e.Code == http.StatusForbidden && strings.Contains(description, "bot was blocked")
+	case ErrChatMigrated:
+		return e.Parameters != nil && e.Parameters.MigrateToChatID != 0
+	}
+
+	return false
+}
+
+// RetryAfter retorna el tiempo que Telegram pide esperar antes de reintentar,
+// o cero si la respuesta no lo indica.
+func (e *APIError) RetryAfter() time.Duration {
+	if e.Parameters == nil {
+		return 0
+	}
+	return time.Duration(e.Parameters.RetryAfter) * time.Second
+}
+
+// newAPIError construye un APIError a partir de una respuesta con ok=false.
+func newAPIError(method string, resp *Response) *APIError {
+	return &APIError{
+		Method:      method,
+		Code:        resp.ErrorCode,
+		Description: resp.Description,
+		Parameters:  resp.Parameters,
+	}
+}
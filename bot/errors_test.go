@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *APIError
+		target   error
+		wantIsIt bool
+	}{
+		{
+			name:     "forbidden",
+			err:      &APIError{Code: 403, Description: "Forbidden: bot was blocked by the user"},
+			target:   ErrForbidden,
+			wantIsIt: true,
+		},
+		{
+			name:     "bot blocked",
+			err:      &APIError{Code: 403, Description: "Forbidden: bot was blocked by the user"},
+			target:   ErrBotBlocked,
+			wantIsIt: true,
+		},
+		{
+			name:     "forbidden is not bot blocked",
+			err:      &APIError{Code: 403, Description: "Forbidden: bot is not a member of the channel chat"},
+			target:   ErrBotBlocked,
+			wantIsIt: false,
+		},
+		{
+			name:     "chat not found",
+			err:      &APIError{Code: 400, Description: "Bad Request: chat not found"},
+			target:   ErrChatNotFound,
+			wantIsIt: true,
+		},
+		{
+			name:     "chat not found is bad request",
+			err:      &APIError{Code: 400, Description: "Bad Request: chat not found"},
+			target:   ErrBadRequest,
+			wantIsIt: true,
+		},
+		{
+			name:     "bad request is not forbidden",
+			err:      &APIError{Code: 400, Description: "Bad Request: message text is empty"},
+			target:   ErrForbidden,
+			wantIsIt: false,
+		},
+		{
+			name:     "too many requests",
+			err:      &APIError{Code: 429, Description: "Too Many Requests: retry after 5", Parameters: &ResponseParameters{RetryAfter: 5}},
+			target:   ErrTooManyRequests,
+			wantIsIt: true,
+		},
+		{
+			name:     "chat migrated",
+			err:      &APIError{Code: 400, Description: "Bad Request: group chat was upgraded to a supergroup chat", Parameters: &ResponseParameters{MigrateToChatID: -1001234}},
+			target:   ErrChatMigrated,
+			wantIsIt: true,
+		},
+		{
+			name:     "server error",
+			err:      &APIError{Code: 502, Description: "Bad Gateway"},
+			target:   ErrServerError,
+			wantIsIt: true,
+		},
+		{
+			name:     "unrelated error",
+			err:      &APIError{Code: 401, Description: "Unauthorized"},
+			target:   context.Canceled,
+			wantIsIt: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.wantIsIt {
+				t.Errorf("expected errors.Is=%v, got %v", tt.wantIsIt, got)
+			}
+		})
+	}
+}
+
+func TestAPIError_RetryAfter(t *testing.T) {
+	err := &APIError{Code: 429, Parameters: &ResponseParameters{RetryAfter: 7}}
+	if got := err.RetryAfter(); got != 7*time.Second {
+		t.Errorf("expected 7s, got %v", got)
+	}
+
+	err = &APIError{Code: 400}
+	if got := err.RetryAfter(); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+}
+
+func TestBot_makeRequest_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 3","parameters":{"retry_after":3}}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	_, err := bot.makeRequest(context.Background(), "sendMessage", SendMessageRequest{ChatID: 123, Text: "Hello"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("expected ErrTooManyRequests, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+
+	if apiErr.Method != "sendMessage" {
+		t.Errorf("expected method sendMessage, got %s", apiErr.Method)
+	}
+
+	if apiErr.Code != 429 {
+		t.Errorf("expected code 429, got %d", apiErr.Code)
+	}
+
+	if apiErr.RetryAfter() != 3*time.Second {
+		t.Errorf("expected retry after 3s, got %v", apiErr.RetryAfter())
+	}
+}
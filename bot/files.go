@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxDownloadSize es el tamaño máximo que la Bot API permite descargar
+// con getFile (20 MB).
+const DefaultMaxDownloadSize int64 = 20 << 20
+
+// ErrFileTooLarge indica que el archivo supera el tamaño máximo de descarga
+// configurado con WithMaxDownloadSize.
+var ErrFileTooLarge = errors.New("file too large")
+
+// WithMaxDownloadSize configura el tamaño máximo en bytes que DownloadFile
+// acepta descargar. Por defecto es DefaultMaxDownloadSize; un servidor local
+// de la Bot API permite archivos más grandes.
+func WithMaxDownloadSize(size int64) BotOption {
+	return func(b *Bot) {
+		if size > 0 {
+			b.maxDownloadSize = size
+		}
+	}
+}
+
+// GetFile obtiene la información de un archivo, incluido el file_path para
+// descargarlo. El file_path es válido por al menos una hora.
+func (b *Bot) GetFile(ctx context.Context, fileID string) (*File, error) {
+	resp, err := b.makeRequest(ctx, "getFile", map[string]string{"file_id": fileID})
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := json.Unmarshal(resp.Result, &file); err != nil {
+		return nil, fmt.Errorf("error unmarshaling file: %w", err)
+	}
+
+	return &file, nil
+}
+
+// DownloadFile descarga el archivo indicado y copia su contenido en w a medida
+// que se recibe. Si el archivo supera el tamaño máximo de descarga retorna
+// ErrFileTooLarge; en ese caso w puede haber recibido parte del contenido.
+//
+// Ejemplo:
+//
+//	var buf bytes.Buffer
+//	if err := b.DownloadFile(ctx, msg.Document.FileID, &buf); err != nil {
+//	    return err
+//	}
+func (b *Bot) DownloadFile(ctx context.Context, fileID string, w io.Writer) error {
+	file, err := b.GetFile(ctx, fileID)
+	if err != nil {
+		return err
+	}
+
+	limit := b.maxDownloadSize
+	if limit <= 0 {
+		limit = DefaultMaxDownloadSize
+	}
+
+	if file.FileSize > limit {
+		return fmt.Errorf("%w: %d bytes", ErrFileTooLarge, file.FileSize)
+	}
+
+	if file.FilePath == "" {
+		return fmt.Errorf("file %s has no file_path", fileID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.fileURL(file.FilePath), nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	b.logger.Debug("Descargando archivo",
+		slog.String("file_id", fileID),
+		slog.Int64("file_size", file.FileSize),
+	)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		b.logger.Error("Error descargando archivo",
+			slog.String("file_id", fileID),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("error downloading file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return downloadError(resp)
+	}
+
+	// Se lee un byte más que el límite para detectar archivos que lo superan
+	// aunque el file_size informado no lo indicara.
+	n, err := io.Copy(w, io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return fmt.Errorf("error downloading file: %w", err)
+	}
+
+	if n > limit {
+		return fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, limit)
+	}
+
+	return nil
+}
+
+// fileURL construye la URL de descarga a partir de apiBaseURL, de modo que
+// funcione también con un servidor local de la Bot API o en tests.
+func (b *Bot) fileURL(filePath string) string {
+	base := strings.Replace(b.apiBaseURL, "/bot%s/", "/file/bot%s/", 1)
+	return fmt.Sprintf(base, b.token, filePath)
+}
+
+// downloadError convierte una respuesta fallida del endpoint de archivos en
+// un APIError, usando la descripción del body si viene en formato JSON.
+func downloadError(resp *http.Response) error {
+	var apiResp Response
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err := json.Unmarshal(body, &apiResp); err == nil && apiResp.ErrorCode != 0 {
+		return newAPIError("downloadFile", &apiResp)
+	}
+
+	return &APIError{
+		Method:      "downloadFile",
+		Code:        resp.StatusCode,
+		Description: http.StatusText(resp.StatusCode),
+	}
+}
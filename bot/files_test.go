@@ -0,0 +1,184 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cancelWriter cancela el contexto al recibir el primer bloque de datos.
+type cancelWriter struct {
+	cancel context.CancelFunc
+	buf    bytes.Buffer
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return w.buf.Write(p)
+}
+
+func TestBot_DownloadFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		getFile       string
+		fileStatus    int
+		fileBody      string
+		maxSize       int64
+		want          string
+		wantErr       error
+		wantDownloads int
+	}{
+		{
+			name:          "success",
+			getFile:       `{"ok":true,"result":{"file_id":"f1","file_unique_id":"u1","file_size":11,"file_path":"documents/file_1.csv"}}`,
+			fileStatus:    http.StatusOK,
+			fileBody:      "a,b\n1,2\n3,4",
+			want:          "a,b\n1,2\n3,4",
+			wantDownloads: 1,
+		},
+		{
+			name:          "reported size over limit",
+			getFile:       `{"ok":true,"result":{"file_id":"f1","file_unique_id":"u1","file_size":100,"file_path":"documents/file_1.csv"}}`,
+			maxSize:       10,
+			wantErr:       ErrFileTooLarge,
+			wantDownloads: 0,
+		},
+		{
+			name:          "body over limit",
+			getFile:       `{"ok":true,"result":{"file_id":"f1","file_unique_id":"u1","file_path":"documents/file_1.csv"}}`,
+			fileStatus:    http.StatusOK,
+			fileBody:      strings.Repeat("x", 20),
+			maxSize:       10,
+			wantErr:       ErrFileTooLarge,
+			wantDownloads: 1,
+		},
+		{
+			name:          "file not found",
+			getFile:       `{"ok":true,"result":{"file_id":"f1","file_unique_id":"u1","file_path":"documents/file_1.csv"}}`,
+			fileStatus:    http.StatusNotFound,
+			fileBody:      `{"ok":false,"error_code":404,"description":"Not Found"}`,
+			wantErr:       ErrNotFound,
+			wantDownloads: 1,
+		},
+		{
+			name:          "invalid file_id",
+			getFile:       `{"ok":false,"error_code":400,"description":"Bad Request: invalid file_id"}`,
+			wantErr:       ErrBadRequest,
+			wantDownloads: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloads := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/bottest-token/getFile":
+					if strings.Contains(tt.getFile, `"ok":false`) {
+						w.WriteHeader(http.StatusBadRequest)
+					}
+					w.Write([]byte(tt.getFile))
+				case "/file/bottest-token/documents/file_1.csv":
+					downloads++
+					if r.Method != http.MethodGet {
+						t.Errorf("expected GET, got %s", r.Method)
+					}
+					w.WriteHeader(tt.fileStatus)
+					w.Write([]byte(tt.fileBody))
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			bot := &Bot{
+				token:           "test-token",
+				client:          &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL:      server.URL + "/bot%s/%s",
+				logger:          testLogger(),
+				maxDownloadSize: tt.maxSize,
+			}
+
+			var buf bytes.Buffer
+			err := bot.DownloadFile(context.Background(), "f1", &buf)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if buf.String() != tt.want {
+				t.Errorf("expected content %q, got %q", tt.want, buf.String())
+			}
+
+			if downloads != tt.wantDownloads {
+				t.Errorf("expected %d downloads, got %d", tt.wantDownloads, downloads)
+			}
+		})
+	}
+}
+
+func TestBot_DownloadFile_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getFile") {
+			w.Write([]byte(`{"ok":true,"result":{"file_id":"f1","file_unique_id":"u1","file_path":"videos/big.mp4"}}`))
+			return
+		}
+
+		// Enviar un primer bloque y mantener la descarga abierta
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &cancelWriter{cancel: cancel}
+	err := bot.DownloadFile(ctx, "f1", w)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBot_GetFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":{"file_id":"f1","file_unique_id":"u1","file_size":42,"file_path":"photos/file_2.jpg"}}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	file, err := bot.GetFile(context.Background(), "f1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if file.FilePath != "photos/file_2.jpg" || file.FileSize != 42 {
+		t.Errorf("unexpected file %+v", file)
+	}
+
+	if got := bot.fileURL(file.FilePath); got != server.URL+"/file/bottest-token/photos/file_2.jpg" {
+		t.Errorf("unexpected file URL %s", got)
+	}
+}
@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// RegistryOption configura un CommandRegistry al crearlo.
+type RegistryOption func(*CommandRegistry)
+
+// WithHelpCommand registra un comando /help que lista los comandos con
+// descripción, agrupados por categoría, y muestra el uso detallado de un
+// comando con /help <comando>. Los comandos AdminOnly solo se listan a los
+// administradores del grupo.
+//
+// Ejemplo:
+//
+//	commands := bot.NewCommandRegistry(bot.WithHelpCommand())
+func WithHelpCommand() RegistryOption {
+	return func(cr *CommandRegistry) {
+		cr.Register("help", cr.help,
+			WithDescription("Mostrar los comandos disponibles"),
+			WithLocalizedDescription("en", "Show available commands"),
+			WithArgs(StringArg("command").Optional()),
+		)
+	}
+}
+
+// WithCategory agrupa el comando bajo la categoría indicada en /help. Los
+// comandos sin categoría se listan primero.
+func WithCategory(category string) CommandOption {
+	return func(e *commandEntry) {
+		e.category = category
+	}
+}
+
+// AdminOnly restringe el comando a los administradores del grupo. Para el
+// resto de los usuarios no se ejecuta ni aparece en /help.
+func AdminOnly() CommandOption {
+	return func(e *commandEntry) {
+		e.adminOnly = true
+	}
+}
+
+// help es el handler del comando registrado con WithHelpCommand.
+func (cr *CommandRegistry) help(ctx context.Context, b *Bot, msg *Message) {
+	var lang string
+	if msg.From != nil {
+		lang = msg.From.LanguageCode
+	}
+
+	// La consulta de administrador se hace una sola vez y solo si hace falta
+	var admin *bool
+	isAdmin := func() bool {
+		if admin == nil {
+			result := b.isChatAdmin(ctx, msg)
+			admin = &result
+		}
+		return *admin
+	}
+
+	var text string
+	if name := strings.TrimPrefix(ArgsFromContext(ctx).String("command"), "/"); name != "" {
+		text = cr.commandHelp(name, lang, isAdmin)
+	} else {
+		text = cr.helpText(lang, isAdmin)
+	}
+
+	if _, err := b.SendMessage(ctx, msg.Chat.ID, text); err != nil {
+		b.logger.Error("Error enviando mensaje",
+			slog.Int64("chat_id", msg.Chat.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// helpText lista los comandos visibles agrupados por categoría, en orden de
+// registro.
+func (cr *CommandRegistry) helpText(lang string, isAdmin func() bool) string {
+	var categories []string
+	lines := make(map[string][]string)
+
+	for _, name := range cr.names {
+		entry := cr.registry[name]
+		if entry.description == "" || (entry.adminOnly && !isAdmin()) {
+			continue
+		}
+
+		if _, ok := lines[entry.category]; !ok {
+			categories = append(categories, entry.category)
+		}
+		line := fmt.Sprintf("%s - %s", entry.usage(name), entry.localizedDescription(lang))
+		lines[entry.category] = append(lines[entry.category], line)
+	}
+
+	if len(categories) == 0 {
+		return "No hay comandos disponibles."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Comandos disponibles:\n")
+
+	// Los comandos sin categoría van primero
+	if uncategorized, ok := lines[""]; ok {
+		sb.WriteString("\n" + strings.Join(uncategorized, "\n") + "\n")
+	}
+	for _, category := range categories {
+		if category == "" {
+			continue
+		}
+		sb.WriteString("\n" + category + "\n" + strings.Join(lines[category], "\n") + "\n")
+	}
+
+	sb.WriteString("\nUsa /help <comando> para ver el detalle de un comando.")
+	return sb.String()
+}
+
+// commandHelp describe un comando. Los comandos ocultos se tratan como
+// inexistentes.
+func (cr *CommandRegistry) commandHelp(name, lang string, isAdmin func() bool) string {
+	entry, ok := cr.registry[name]
+	if !ok || entry.description == "" || (entry.adminOnly && !isAdmin()) {
+		return fmt.Sprintf("No existe el comando /%s.", name)
+	}
+
+	text := entry.usage(name) + "\n" + entry.localizedDescription(lang)
+	if entry.adminOnly {
+		text += "\nSolo para administradores."
+	}
+	return text
+}
+
+// usage retorna la forma de uso del comando.
+func (e *commandEntry) usage(name string) string {
+	return e.args.usage(name)
+}
+
+// localizedDescription retorna la descripción en el idioma indicado, o la
+// descripción por defecto si no está traducida.
+func (e *commandEntry) localizedDescription(lang string) string {
+	if localized := e.descriptions[lang]; localized != "" {
+		return localized
+	}
+	return e.description
+}
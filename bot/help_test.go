@@ -0,0 +1,213 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// helpServer responde getChatMember con el estado indicado y guarda el texto
+// del último mensaje enviado.
+func helpServer(t *testing.T, status string, sent *string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.HasSuffix(r.URL.Path, "/getChatMember") {
+			w.Write([]byte(`{"ok":true,"result":{"status":"` + status + `","user":{"id":7}}}`))
+			return
+		}
+
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		*sent = req.Text
+		w.Write([]byte(`{"ok":true,"result":{"message_id":2}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func helpRegistry() *CommandRegistry {
+	noop := func(context.Context, *Bot, *Message) {}
+
+	registry := NewCommandRegistry(WithHelpCommand())
+	registry.Register("start", noop,
+		WithDescription("Iniciar el bot"),
+		WithLocalizedDescription("en", "Start the bot"),
+	)
+	registry.Register("note", noop,
+		WithDescription("Guardar una nota"),
+		WithCategory("Notas"),
+		WithArgs(RestArg("text")),
+	)
+	registry.Register("mute", noop,
+		WithDescription("Silenciar a un usuario"),
+		WithCategory("Moderación"),
+		WithArgs(StringArg("user"), DurationArg("duration")),
+		AdminOnly(),
+	)
+	registry.Register("debug", noop) // Sin descripción: no aparece en la ayuda
+
+	return registry
+}
+
+func TestCommandRegistry_Help(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		chatType string
+		status   string
+		lang     string
+		want     string
+	}{
+		{
+			name:     "member does not see admin commands",
+			text:     "/help",
+			chatType: "group",
+			status:   "member",
+			want: "Comandos disponibles:\n" +
+				"\n/help [command] - Mostrar los comandos disponibles\n/start - Iniciar el bot\n" +
+				"\nNotas\n/note <text...> - Guardar una nota\n" +
+				"\nUsa /help <comando> para ver el detalle de un comando.",
+		},
+		{
+			name:     "admin sees admin commands",
+			text:     "/help",
+			chatType: "supergroup",
+			status:   "administrator",
+			want: "Comandos disponibles:\n" +
+				"\n/help [command] - Mostrar los comandos disponibles\n/start - Iniciar el bot\n" +
+				"\nNotas\n/note <text...> - Guardar una nota\n" +
+				"\nModeración\n/mute <user> <duration> - Silenciar a un usuario\n" +
+				"\nUsa /help <comando> para ver el detalle de un comando.",
+		},
+		{
+			name:     "localized descriptions",
+			text:     "/help",
+			chatType: "private",
+			lang:     "en",
+			want: "Comandos disponibles:\n" +
+				"\n/help [command] - Show available commands\n/start - Start the bot\n" +
+				"\nNotas\n/note <text...> - Guardar una nota\n" +
+				"\nUsa /help <comando> para ver el detalle de un comando.",
+		},
+		{
+			name:     "command detail",
+			text:     "/help /note",
+			chatType: "private",
+			want:     "/note <text...>\nGuardar una nota",
+		},
+		{
+			name:     "admin command detail",
+			text:     "/help mute",
+			chatType: "group",
+			status:   "creator",
+			want:     "/mute <user> <duration>\nSilenciar a un usuario\nSolo para administradores.",
+		},
+		{
+			name:     "admin command hidden in private chat",
+			text:     "/help mute",
+			chatType: "private",
+			want:     "No existe el comando /mute.",
+		},
+		{
+			name:     "unknown command",
+			text:     "/help debug",
+			chatType: "private",
+			want:     "No existe el comando /debug.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			server := helpServer(t, tt.status, &sent)
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			msg := commandMessage(tt.text)
+			msg.Chat.Type = tt.chatType
+			msg.From = &User{ID: 7, LanguageCode: tt.lang}
+
+			if !helpRegistry().Execute(context.Background(), bot, msg) {
+				t.Fatal("expected /help to be handled")
+			}
+
+			if sent != tt.want {
+				t.Errorf("expected help:\n%s\ngot:\n%s", tt.want, sent)
+			}
+		})
+	}
+}
+
+func TestCommandRegistry_Help_Empty(t *testing.T) {
+	registry := NewCommandRegistry()
+	registry.Register("debug", func(context.Context, *Bot, *Message) {})
+
+	if got := registry.helpText("", func() bool { return true }); got != "No hay comandos disponibles." {
+		t.Errorf("unexpected help text %q", got)
+	}
+}
+
+func TestCommandRegistry_Execute_AdminOnly(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		senderChat bool
+		wantCalled bool
+	}{
+		{name: "administrator", status: "administrator", wantCalled: true},
+		{name: "creator", status: "creator", wantCalled: true},
+		{name: "anonymous admin", senderChat: true, wantCalled: true},
+		{name: "member", status: "member"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			server := helpServer(t, tt.status, &sent)
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			called := false
+			registry := NewCommandRegistry()
+			registry.Register("ban", func(context.Context, *Bot, *Message) {
+				called = true
+			}, AdminOnly())
+
+			msg := commandMessage("/ban")
+			msg.Chat.Type = "supergroup"
+			msg.From = &User{ID: 7}
+			if tt.senderChat {
+				msg.SenderChat = &Chat{ID: msg.Chat.ID}
+			}
+
+			if !registry.Execute(context.Background(), bot, msg) {
+				t.Fatal("expected command to be handled")
+			}
+
+			if called != tt.wantCalled {
+				t.Errorf("expected handler called=%v, got %v", tt.wantCalled, called)
+			}
+
+			if !tt.wantCalled && sent != "Este comando es solo para administradores." {
+				t.Errorf("unexpected reply %q", sent)
+			}
+		})
+	}
+}
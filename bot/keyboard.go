@@ -0,0 +1,58 @@
+package bot
+
+func (*InlineKeyboardMarkup) replyMarkup() {}
+
+// InlineKeyboardBuilder construye un InlineKeyboardMarkup fila por fila.
+//
+// Ejemplo:
+//
+//	keyboard := bot.NewInlineKeyboard().
+//	    Row(bot.CallbackButton("Sí", "confirm:yes"), bot.CallbackButton("No", "confirm:no")).
+//	    Row(bot.URLButton("Ayuda", "https://example.com/help")).
+//	    Build()
+type InlineKeyboardBuilder struct {
+	rows [][]InlineKeyboardButton
+}
+
+// NewInlineKeyboard crea un builder de teclado inline vacío.
+func NewInlineKeyboard() *InlineKeyboardBuilder {
+	return &InlineKeyboardBuilder{}
+}
+
+// Row agrega una fila con los botones indicados. Las filas vacías se ignoran.
+func (k *InlineKeyboardBuilder) Row(buttons ...InlineKeyboardButton) *InlineKeyboardBuilder {
+	if len(buttons) > 0 {
+		k.rows = append(k.rows, buttons)
+	}
+	return k
+}
+
+// Build retorna el teclado construido.
+func (k *InlineKeyboardBuilder) Build() *InlineKeyboardMarkup {
+	rows := make([][]InlineKeyboardButton, len(k.rows))
+	copy(rows, k.rows)
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// CallbackButton crea un botón que envía un callback query con data al ser
+// presionado. Telegram limita data a 64 bytes.
+func CallbackButton(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+// URLButton crea un botón que abre la URL indicada.
+func URLButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// SwitchInlineButton crea un botón que le pide al usuario elegir un chat e
+// inicia una consulta inline al bot con query.
+func SwitchInlineButton(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+// SwitchInlineCurrentChatButton crea un botón que inicia una consulta inline
+// al bot en el chat actual.
+func SwitchInlineCurrentChatButton(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query}
+}
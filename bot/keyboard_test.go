@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInlineKeyboardBuilder(t *testing.T) {
+	keyboard := NewInlineKeyboard().
+		Row(CallbackButton("Sí", "confirm:yes"), CallbackButton("No", "confirm:no")).
+		Row().
+		Row(URLButton("Ayuda", "https://example.com/help")).
+		Build()
+
+	if len(keyboard.InlineKeyboard) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(keyboard.InlineKeyboard))
+	}
+
+	if len(keyboard.InlineKeyboard[0]) != 2 {
+		t.Errorf("expected 2 buttons in first row, got %d", len(keyboard.InlineKeyboard[0]))
+	}
+
+	data, err := json.Marshal(keyboard)
+	if err != nil {
+		t.Fatalf("error marshaling keyboard: %v", err)
+	}
+
+	want := `{"inline_keyboard":[[{"text":"Sí","callback_data":"confirm:yes"},{"text":"No","callback_data":"confirm:no"}],[{"text":"Ayuda","url":"https://example.com/help"}]]}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n got: %s\nwant: %s", data, want)
+	}
+}
+
+func TestSwitchInlineButton_EmptyQuery(t *testing.T) {
+	data, err := json.Marshal(SwitchInlineCurrentChatButton("Buscar", ""))
+	if err != nil {
+		t.Fatalf("error marshaling button: %v", err)
+	}
+
+	// Un query vacío es válido y debe enviarse
+	want := `{"text":"Buscar","switch_inline_query_current_chat":""}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
+
+func TestBot_SendMessage_WithReplyMarkup(t *testing.T) {
+	var received map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	keyboard := NewInlineKeyboard().Row(CallbackButton("OK", "ok")).Build()
+	if _, err := bot.SendMessage(context.Background(), 123, "Hello", WithReplyMarkup(keyboard)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"inline_keyboard":[[{"text":"OK","callback_data":"ok"}]]}`
+	if got := string(received["reply_markup"]); got != want {
+		t.Errorf("expected reply_markup %s, got %s", want, got)
+	}
+}
+
+func TestBot_SendMessage_WithoutReplyMarkup(t *testing.T) {
+	var received map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	if _, err := bot.SendMessage(context.Background(), 123, "Hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := received["reply_markup"]; ok {
+		t.Error("expected reply_markup to be omitted")
+	}
+}
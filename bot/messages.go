@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// EditMessageText reemplaza el texto de un mensaje. Retorna el mensaje
+// editado, o nil si el mensaje fue enviado en modo inline.
+//
+// Ejemplo:
+//
+//	_, err := b.EditMessageText(ctx, bot.EditMessageTextRequest{
+//	    ChatID:    msg.Chat.ID,
+//	    MessageID: msg.MessageID,
+//	    Text:      "Procesando... 50%",
+//	})
+func (b *Bot) EditMessageText(ctx context.Context, req EditMessageTextRequest) (*Message, error) {
+	return b.editRequest(ctx, "editMessageText", req)
+}
+
+// EditMessageReplyMarkup reemplaza o elimina el teclado inline de un mensaje.
+// Retorna el mensaje editado, o nil si el mensaje fue enviado en modo inline.
+func (b *Bot) EditMessageReplyMarkup(ctx context.Context, req EditMessageReplyMarkupRequest) (*Message, error) {
+	return b.editRequest(ctx, "editMessageReplyMarkup", req)
+}
+
+// DeleteMessage elimina un mensaje. Los bots solo pueden eliminar mensajes
+// de menos de 48 horas.
+func (b *Bot) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	payload := DeleteMessageRequest{
+		ChatID:    chatID,
+		MessageID: messageID,
+	}
+
+	_, err := b.makeRequest(ctx, "deleteMessage", payload)
+	return err
+}
+
+// DeleteMessages elimina varios mensajes de un chat en un solo request.
+// Los mensajes que no se pueden eliminar se ignoran.
+func (b *Bot) DeleteMessages(ctx context.Context, chatID int64, messageIDs []int) error {
+	payload := DeleteMessagesRequest{
+		ChatID:     chatID,
+		MessageIDs: messageIDs,
+	}
+
+	_, err := b.makeRequest(ctx, "deleteMessages", payload)
+	return err
+}
+
+// ForwardMessage reenvía un mensaje y retorna el mensaje enviado.
+func (b *Bot) ForwardMessage(ctx context.Context, req ForwardMessageRequest) (*Message, error) {
+	return b.sendRequest(ctx, "forwardMessage", req)
+}
+
+// CopyMessage copia un mensaje sin el enlace al original. Telegram solo
+// retorna el ID del mensaje nuevo.
+func (b *Bot) CopyMessage(ctx context.Context, req CopyMessageRequest) (*MessageID, error) {
+	resp, err := b.makeRequest(ctx, "copyMessage", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var id MessageID
+	if err := json.Unmarshal(resp.Result, &id); err != nil {
+		return nil, fmt.Errorf("error unmarshaling message id: %w", err)
+	}
+
+	return &id, nil
+}
+
+// editRequest ejecuta un método de edición. Para mensajes inline Telegram
+// retorna true en lugar del mensaje editado.
+func (b *Bot) editRequest(ctx context.Context, method string, payload any) (*Message, error) {
+	resp, err := b.makeRequest(ctx, method, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(resp.Result, []byte("true")) {
+		return nil, nil
+	}
+
+	var msg Message
+	if err := json.Unmarshal(resp.Result, &msg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling message: %w", err)
+	}
+
+	return &msg, nil
+}
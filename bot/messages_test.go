@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBot_MessageMethods(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		response    string
+		call        func(b *Bot) (any, error)
+		wantPayload map[string]string
+		check       func(t *testing.T, result any)
+	}{
+		{
+			name:     "edit message text",
+			method:   "editMessageText",
+			response: `{"ok":true,"result":{"message_id":10,"chat":{"id":123,"type":"private"},"date":1,"text":"50%"}}`,
+			call: func(b *Bot) (any, error) {
+				return b.EditMessageText(context.Background(), EditMessageTextRequest{
+					ChatID:    123,
+					MessageID: 10,
+					Text:      "50%",
+					ParseMode: ParseModeHTML,
+				})
+			},
+			wantPayload: map[string]string{
+				"chat_id":    `123`,
+				"message_id": `10`,
+				"text":       `"50%"`,
+				"parse_mode": `"HTML"`,
+			},
+			check: func(t *testing.T, result any) {
+				msg := result.(*Message)
+				if msg == nil || msg.Text != "50%" {
+					t.Errorf("expected edited message, got %+v", msg)
+				}
+			},
+		},
+		{
+			name:     "edit inline message text",
+			method:   "editMessageText",
+			response: `{"ok":true,"result":true}`,
+			call: func(b *Bot) (any, error) {
+				return b.EditMessageText(context.Background(), EditMessageTextRequest{
+					InlineMessageID: "inline-1",
+					Text:            "done",
+				})
+			},
+			wantPayload: map[string]string{
+				"inline_message_id": `"inline-1"`,
+				"text":              `"done"`,
+			},
+			check: func(t *testing.T, result any) {
+				if msg := result.(*Message); msg != nil {
+					t.Errorf("expected nil message for inline edit, got %+v", msg)
+				}
+			},
+		},
+		{
+			name:     "edit reply markup",
+			method:   "editMessageReplyMarkup",
+			response: `{"ok":true,"result":{"message_id":10,"chat":{"id":123,"type":"private"},"date":1}}`,
+			call: func(b *Bot) (any, error) {
+				return b.EditMessageReplyMarkup(context.Background(), EditMessageReplyMarkupRequest{
+					ChatID:      123,
+					MessageID:   10,
+					ReplyMarkup: NewInlineKeyboard().Row(CallbackButton("Next", "page:2")).Build(),
+				})
+			},
+			wantPayload: map[string]string{
+				"reply_markup": `{"inline_keyboard":[[{"text":"Next","callback_data":"page:2"}]]}`,
+			},
+		},
+		{
+			name:     "delete message",
+			method:   "deleteMessage",
+			response: `{"ok":true,"result":true}`,
+			call: func(b *Bot) (any, error) {
+				return nil, b.DeleteMessage(context.Background(), 123, 10)
+			},
+			wantPayload: map[string]string{
+				"chat_id":    `123`,
+				"message_id": `10`,
+			},
+		},
+		{
+			name:     "delete messages",
+			method:   "deleteMessages",
+			response: `{"ok":true,"result":true}`,
+			call: func(b *Bot) (any, error) {
+				return nil, b.DeleteMessages(context.Background(), 123, []int{10, 11})
+			},
+			wantPayload: map[string]string{
+				"message_ids": `[10,11]`,
+			},
+		},
+		{
+			name:     "forward message",
+			method:   "forwardMessage",
+			response: `{"ok":true,"result":{"message_id":20,"chat":{"id":456,"type":"private"},"date":1}}`,
+			call: func(b *Bot) (any, error) {
+				return b.ForwardMessage(context.Background(), ForwardMessageRequest{
+					ChatID:     456,
+					FromChatID: 123,
+					MessageID:  10,
+				})
+			},
+			wantPayload: map[string]string{
+				"chat_id":      `456`,
+				"from_chat_id": `123`,
+				"message_id":   `10`,
+			},
+			check: func(t *testing.T, result any) {
+				if msg := result.(*Message); msg.MessageID != 20 {
+					t.Errorf("expected MessageID 20, got %d", msg.MessageID)
+				}
+			},
+		},
+		{
+			name:     "copy message",
+			method:   "copyMessage",
+			response: `{"ok":true,"result":{"message_id":30}}`,
+			call: func(b *Bot) (any, error) {
+				return b.CopyMessage(context.Background(), CopyMessageRequest{
+					ChatID:      456,
+					FromChatID:  123,
+					MessageID:   10,
+					Caption:     "copia",
+					SendOptions: SendOptions{DisableNotification: true},
+				})
+			},
+			wantPayload: map[string]string{
+				"caption":              `"copia"`,
+				"disable_notification": `true`,
+			},
+			check: func(t *testing.T, result any) {
+				if id := result.(*MessageID); id.MessageID != 30 {
+					t.Errorf("expected MessageID 30, got %d", id.MessageID)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received map[string]json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/"+tt.method) {
+					t.Errorf("expected method %s, got %s", tt.method, r.URL.Path)
+				}
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			result, err := tt.call(bot)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for field, want := range tt.wantPayload {
+				if got := string(received[field]); got != want {
+					t.Errorf("expected %s=%s, got %s", field, want, got)
+				}
+			}
+
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+		})
+	}
+}
+
+func TestBot_EditMessageText_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: message is not modified"}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	msg, err := bot.EditMessageText(context.Background(), EditMessageTextRequest{ChatID: 1, MessageID: 1, Text: "same"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if msg != nil {
+		t.Errorf("expected nil message, got %+v", msg)
+	}
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"time"
+)
+
+// Handler procesa una actualización. Es la forma que reciben y retornan los
+// middlewares.
+type Handler func(ctx context.Context, b *Bot, update *Update) error
+
+// Middleware envuelve un Handler para agregar comportamiento antes o después
+// de procesar cada actualización: autenticación, métricas, trazas, etc. Un
+// middleware puede cortar la cadena retornando sin llamar a next.
+//
+// Ejemplo:
+//
+//	func onlyUsers(allowed map[int64]bool) bot.Middleware {
+//	    return func(next bot.Handler) bot.Handler {
+//	        return func(ctx context.Context, b *bot.Bot, update *bot.Update) error {
+//	            if from := update.From(); from == nil || !allowed[from.ID] {
+//	                return nil
+//	            }
+//	            return next(ctx, b, update)
+//	        }
+//	    }
+//	}
+type Middleware func(next Handler) Handler
+
+// WithMiddleware agrega middlewares que envuelven el procesamiento de todas
+// las actualizaciones. Se ejecutan en el orden indicado: el primero es el más
+// externo.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithMiddleware(
+//	    bot.Recover(),
+//	    bot.Logging(),
+//	    bot.Timeout(30*time.Second),
+//	))
+func WithMiddleware(middlewares ...Middleware) BotOption {
+	return func(b *Bot) {
+		b.middlewares = append(b.middlewares, middlewares...)
+	}
+}
+
+// WithCommandMiddleware agrega middlewares que envuelven solo la ejecución del
+// comando, incluida la validación de sus argumentos. Se ejecutan después de
+// los middlewares globales configurados con WithMiddleware.
+//
+// Ejemplo:
+//
+//	commands.Register("ban", commandBan,
+//	    bot.WithCommandMiddleware(bot.ChatTypes("group", "supergroup")),
+//	)
+func WithCommandMiddleware(middlewares ...Middleware) CommandOption {
+	return func(e *commandEntry) {
+		e.middlewares = append(e.middlewares, middlewares...)
+	}
+}
+
+// chain aplica los middlewares al handler, de modo que el primero de la lista
+// sea el más externo.
+func chain(h Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+type updateContextKey struct{}
+
+// updateFromContext retorna el update en proceso, guardado por handleUpdate.
+func updateFromContext(ctx context.Context) *Update {
+	update, _ := ctx.Value(updateContextKey{}).(*Update)
+	return update
+}
+
+// Recover convierte los panics del handler en errores, para que un handler
+// defectuoso no termine el proceso. El panic se registra con su stack trace.
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, b *Bot, update *Update) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					b.logger.Error("Panic procesando update",
+						append(updateAttrs(update),
+							slog.Any("panic", r),
+							slog.String("stack", string(debug.Stack())),
+						)...,
+					)
+					err = fmt.Errorf("handler panic: %v", r)
+				}
+			}()
+
+			return next(ctx, b, update)
+		}
+	}
+}
+
+// Logging registra cada actualización procesada con su tipo, chat, usuario,
+// duración y error, si lo hubo.
+func Logging() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, b *Bot, update *Update) error {
+			start := time.Now()
+			err := next(ctx, b, update)
+
+			attrs := append(updateAttrs(update), slog.Duration("duration", time.Since(start)))
+			if err != nil {
+				b.logger.Error("Error procesando update", append(attrs, slog.String("error", err.Error()))...)
+				return err
+			}
+
+			b.logger.Info("Update procesado", attrs...)
+			return nil
+		}
+	}
+}
+
+// Timeout limita el tiempo de procesamiento de cada actualización: el
+// contexto que recibe el handler se cancela al pasar d. Los requests a la API
+// en curso se cancelan, pero el handler debe respetar el contexto para
+// terminar a tiempo.
+func Timeout(d time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, b *Bot, update *Update) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			return next(ctx, b, update)
+		}
+	}
+}
+
+// ChatTypes procesa solo las actualizaciones de chats de los tipos indicados
+// ("private", "group", "supergroup" o "channel") y descarta el resto, incluidas
+// las que no pertenecen a un chat, como las inline queries.
+func ChatTypes(types ...string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, b *Bot, update *Update) error {
+			chat := update.Chat()
+			if chat == nil || !slices.Contains(types, chat.Type) {
+				return nil
+			}
+
+			return next(ctx, b, update)
+		}
+	}
+}
+
+// updateAttrs retorna los atributos de log que identifican la actualización.
+func updateAttrs(update *Update) []any {
+	attrs := []any{
+		slog.Int("update_id", update.UpdateID),
+		slog.String("type", update.Type()),
+	}
+	if chat := update.Chat(); chat != nil {
+		attrs = append(attrs, slog.Int64("chat_id", chat.ID))
+	}
+	if from := update.From(); from != nil {
+		attrs = append(attrs, slog.Int64("user_id", from.ID))
+	}
+	return attrs
+}
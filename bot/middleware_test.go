@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordMiddleware registra en calls cuándo entra y sale de la cadena.
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, b *Bot, update *Update) error {
+			*calls = append(*calls, name+" before")
+			err := next(ctx, b, update)
+			*calls = append(*calls, name+" after")
+			return err
+		}
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	handler := func(context.Context, *Bot, *Update) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+
+	h := chain(handler, []Middleware{recordMiddleware("a", &calls), recordMiddleware("b", &calls)})
+	if err := h(context.Background(), &Bot{logger: testLogger()}, &Update{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"a before", "b before", "handler", "b after", "a after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	h := Recover()(func(context.Context, *Bot, *Update) error {
+		panic("boom")
+	})
+
+	err := h(context.Background(), &Bot{logger: testLogger()}, &Update{UpdateID: 1})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected panic converted to error, got %v", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	var deadline time.Time
+	h := Timeout(time.Minute)(func(ctx context.Context, b *Bot, update *Update) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+
+	h(context.Background(), &Bot{logger: testLogger()}, &Update{})
+
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > time.Minute {
+		t.Errorf("expected deadline within a minute, got %v", remaining)
+	}
+}
+
+func TestChatTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *Update
+		want   bool
+	}{
+		{
+			name:   "allowed chat type",
+			update: &Update{Message: &Message{Chat: &Chat{ID: 1, Type: "group"}}},
+			want:   true,
+		},
+		{
+			name:   "filtered chat type",
+			update: &Update{Message: &Message{Chat: &Chat{ID: 1, Type: "private"}}},
+			want:   false,
+		},
+		{
+			name:   "callback query from allowed chat",
+			update: &Update{CallbackQuery: &CallbackQuery{Message: &Message{Chat: &Chat{ID: 1, Type: "supergroup"}}}},
+			want:   true,
+		},
+		{
+			name:   "update without chat",
+			update: &Update{InlineQuery: &InlineQuery{ID: "1"}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := ChatTypes("group", "supergroup")(func(context.Context, *Bot, *Update) error {
+				called = true
+				return nil
+			})
+
+			if err := h(context.Background(), &Bot{logger: testLogger()}, tt.update); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if called != tt.want {
+				t.Errorf("expected handler called=%v, got %v", tt.want, called)
+			}
+		})
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	bot := &Bot{logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	h := Logging()(func(context.Context, *Bot, *Update) error {
+		return errors.New("fallo")
+	})
+
+	update := &Update{UpdateID: 7, Message: &Message{Chat: &Chat{ID: 123}, From: &User{ID: 9}}}
+	if err := h(context.Background(), bot, update); err == nil {
+		t.Fatal("expected handler error to be returned")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("error decoding log entry: %v", err)
+	}
+
+	want := map[string]any{
+		"level":     "ERROR",
+		"update_id": float64(7),
+		"type":      UpdateTypeMessage,
+		"chat_id":   float64(123),
+		"user_id":   float64(9),
+		"error":     "fallo",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("expected duration attribute")
+	}
+}
+
+func TestBot_WithMiddleware(t *testing.T) {
+	var calls []string
+	registry := NewCommandRegistry()
+	registry.Register("start", func(context.Context, *Bot, *Message) {
+		calls = append(calls, "command")
+		panic("boom")
+	}, WithCommandMiddleware(recordMiddleware("command", &calls)))
+
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithCommandRegistry(registry),
+		WithMiddleware(recordMiddleware("global", &calls), Recover()),
+	)
+
+	update := &Update{UpdateID: 1, Message: commandMessage("/start")}
+	update.Message.From = &User{ID: 2, FirstName: "Test"}
+
+	// Recover evita que el panic del comando termine el test
+	bot.handleUpdate(context.Background(), update)
+
+	want := []string{"global before", "command before", "command", "global after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestWithCommandMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		chatType   string
+		wantCalled bool
+	}{
+		{name: "group", chatType: "group", wantCalled: true},
+		{name: "private", chatType: "private", wantCalled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen *Update
+			inspect := func(next Handler) Handler {
+				return func(ctx context.Context, b *Bot, update *Update) error {
+					seen = update
+					return next(ctx, b, update)
+				}
+			}
+
+			called := false
+			registry := NewCommandRegistry()
+			registry.Register("ban", func(context.Context, *Bot, *Message) {
+				called = true
+			}, WithCommandMiddleware(inspect, ChatTypes("group", "supergroup")))
+
+			bot := NewBot("test-token", WithLogger(testLogger()), WithCommandRegistry(registry))
+
+			update := &Update{UpdateID: 42, Message: commandMessage("/ban")}
+			update.Message.Chat.Type = tt.chatType
+			update.Message.From = &User{ID: 2, FirstName: "Test"}
+			bot.handleUpdate(context.Background(), update)
+
+			if seen != update {
+				t.Errorf("expected command middleware to receive the update, got %+v", seen)
+			}
+
+			if called != tt.wantCalled {
+				t.Errorf("expected command called=%v, got %v", tt.wantCalled, called)
+			}
+		})
+	}
+}
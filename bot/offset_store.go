@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OffsetStore guarda el offset de getUpdates entre reinicios del bot: el ID
+// de la próxima actualización a recibir. Start lo carga antes del primer
+// getUpdates y lo guarda después de despachar cada lote.
+type OffsetStore interface {
+	// Load retorna el offset guardado, o 0 si todavía no hay uno.
+	Load(ctx context.Context) (int, error)
+	// Save guarda el offset.
+	Save(ctx context.Context, offset int) error
+}
+
+// WithOffsetStore configura dónde se guarda el offset de getUpdates. Por
+// defecto se guarda en memoria y se pierde al reiniciar el proceso.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithOffsetStore(bot.NewFileOffsetStore("data/offset")))
+func WithOffsetStore(store OffsetStore) BotOption {
+	return func(b *Bot) {
+		b.offsetStore = store
+	}
+}
+
+// MemoryOffsetStore guarda el offset en memoria. Es el OffsetStore por
+// defecto.
+type MemoryOffsetStore struct {
+	mu     sync.Mutex
+	offset int
+}
+
+// NewMemoryOffsetStore crea un OffsetStore en memoria.
+func NewMemoryOffsetStore() *MemoryOffsetStore {
+	return &MemoryOffsetStore{}
+}
+
+func (s *MemoryOffsetStore) Load(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset, nil
+}
+
+func (s *MemoryOffsetStore) Save(ctx context.Context, offset int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = offset
+	return nil
+}
+
+// FileOffsetStore guarda el offset en un archivo de texto. Cada Save escribe
+// un archivo temporal y lo renombra, de modo que una caída del proceso a
+// mitad de la escritura deja el offset anterior intacto.
+type FileOffsetStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileOffsetStore crea un OffsetStore que guarda el offset en path. El
+// directorio debe existir; el archivo se crea en el primer Save.
+func NewFileOffsetStore(path string) *FileOffsetStore {
+	return &FileOffsetStore{path: path}
+}
+
+func (s *FileOffsetStore) Load(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading offset file: %w", err)
+	}
+
+	offset, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset file %s: %w", s.path, err)
+	}
+
+	return offset, nil
+}
+
+func (s *FileOffsetStore) Save(ctx context.Context, offset int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// El archivo temporal va en el mismo directorio para que Rename sea atómico
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating offset file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.Itoa(offset) + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing offset file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error syncing offset file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing offset file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error replacing offset file: %w", err)
+	}
+
+	return nil
+}
+
+// loadOffset carga el offset guardado. Si el bot ya tiene un offset mayor,
+// por ejemplo al llamar a Start por segunda vez, se conserva ese.
+func (b *Bot) loadOffset(ctx context.Context) error {
+	if b.offsetStore == nil {
+		return nil
+	}
+
+	offset, err := b.offsetStore.Load(ctx)
+	if err != nil {
+		return err
+	}
+	b.offset = max(b.offset, offset)
+
+	return nil
+}
+
+// saveOffset guarda el offset. Un error no detiene al bot: en el peor caso,
+// al reiniciar se vuelven a recibir actualizaciones ya procesadas.
+func (b *Bot) saveOffset(ctx context.Context, offset int) {
+	if b.offsetStore == nil {
+		return
+	}
+
+	if err := b.offsetStore.Save(ctx, offset); err != nil {
+		b.logger.Error("Error guardando offset",
+			slog.Int("offset", offset),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileOffsetStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "offset")
+	ctx := context.Background()
+
+	offset, err := NewFileOffsetStore(path).Load(ctx)
+	if err != nil || offset != 0 {
+		t.Fatalf("expected offset 0 without file, got %d, %v", offset, err)
+	}
+
+	store := NewFileOffsetStore(path)
+	for _, want := range []int{42, 43} {
+		if err := store.Save(ctx, want); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Una instancia nueva simula el reinicio del proceso
+		got, err := NewFileOffsetStore(path).Load(ctx)
+		if err != nil || got != want {
+			t.Errorf("expected offset %d, got %d, %v", want, got, err)
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the offset file, got %d entries", len(entries))
+	}
+}
+
+func TestFileOffsetStore_InterruptedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offset")
+	ctx := context.Background()
+
+	if err := NewFileOffsetStore(path).Save(ctx, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Una caída a mitad de Save deja solo un archivo temporal incompleto
+	if err := os.WriteFile(path+".tmp-123", []byte("9"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	offset, err := NewFileOffsetStore(path).Load(ctx)
+	if err != nil || offset != 42 {
+		t.Errorf("expected previous offset 42, got %d, %v", offset, err)
+	}
+}
+
+func TestFileOffsetStore_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offset")
+	if err := os.WriteFile(path, []byte("no es un número"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileOffsetStore(path).Load(context.Background()); err == nil {
+		t.Error("expected error for an invalid offset file")
+	}
+}
+
+func TestMemoryOffsetStore(t *testing.T) {
+	store := NewMemoryOffsetStore()
+	store.Save(context.Background(), 7)
+
+	if offset, _ := store.Load(context.Background()); offset != 7 {
+		t.Errorf("expected offset 7, got %d", offset)
+	}
+}
+
+// failingOffsetStore simula un proceso que se cae antes de persistir el
+// offset: Save nunca llega al disco.
+type failingOffsetStore struct {
+	OffsetStore
+}
+
+func (failingOffsetStore) Save(context.Context, int) error {
+	return errors.New("process killed")
+}
+
+// runBot inicia un bot con el store indicado contra un servidor que entrega
+// los updates 10 y 11, espera a que procese ambos y lo detiene.
+func runBot(t *testing.T, store OffsetStore) *pollingServer {
+	t.Helper()
+
+	fake := &pollingServer{batch: `{"update_id":10,"message":{"message_id":1,"chat":{"id":1},"from":{"id":1,"first_name":"A"},"text":"a"}},` +
+		`{"update_id":11,"message":{"message_id":2,"chat":{"id":1},"from":{"id":1,"first_name":"A"},"text":"b"}}`}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	handled := make(chan struct{}, 2)
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithOffsetStore(store),
+		OnMessage(func(context.Context, *Bot, *Message) error { handled <- struct{}{}; return nil }),
+	)
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Start(ctx) }()
+
+	fake.firstPoll(t)
+	for range 2 {
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatal("updates were not handled")
+		}
+	}
+
+	cancel()
+	<-done
+	return fake
+}
+
+func TestBot_Start_OffsetStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		crash       bool // El primer proceso se cae antes de guardar el offset
+		wantRestart int  // Offset del primer getUpdates después de reiniciar
+	}{
+		{name: "crash between polling and acknowledging", crash: true, wantRestart: 10},
+		{name: "restart after acknowledging", crash: false, wantRestart: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "offset")
+			if err := NewFileOffsetStore(path).Save(context.Background(), 10); err != nil {
+				t.Fatal(err)
+			}
+
+			var store OffsetStore = NewFileOffsetStore(path)
+			if tt.crash {
+				store = failingOffsetStore{store}
+			}
+
+			first := runBot(t, store)
+			if offset := first.firstPoll(t); offset != 10 {
+				t.Errorf("expected first run to start from the stored offset 10, got %d", offset)
+			}
+
+			restarted := runBot(t, NewFileOffsetStore(path))
+			if offset := restarted.firstPoll(t); offset != tt.wantRestart {
+				t.Errorf("expected restart to poll from offset %d, got %d", tt.wantRestart, offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minChatBuckets es la cantidad de chats a partir de la cual se eliminan los
+// buckets inactivos para que el limitador no crezca indefinidamente.
+const minChatBuckets = 1024
+
+// RateLimit define cuántos mensajes se permiten en un intervalo. Un valor
+// cero deshabilita el límite.
+type RateLimit struct {
+	Count int
+	Per   time.Duration
+}
+
+// RateLimits agrupa los límites de envío que aplica el bot.
+type RateLimits struct {
+	// Global limita el total de mensajes enviados por el bot.
+	Global RateLimit
+	// PrivateChat limita los mensajes enviados a cada chat privado.
+	PrivateChat RateLimit
+	// Group limita los mensajes enviados a cada grupo, supergrupo o canal.
+	Group RateLimit
+}
+
+// DefaultRateLimits retorna los límites recomendados por Telegram:
+// 30 mensajes por segundo en total, 1 por segundo por chat privado y 20 por
+// minuto por grupo.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Global:      RateLimit{Count: 30, Per: time.Second},
+		PrivateChat: RateLimit{Count: 1, Per: time.Second},
+		Group:       RateLimit{Count: 20, Per: time.Minute},
+	}
+}
+
+// WithRateLimiter habilita el limitador de envíos. Los requests que exceden
+// los límites no se descartan: esperan su turno en orden de llegada para
+// cada chat, respetando la cancelación del contexto.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithRateLimiter(bot.DefaultRateLimits()))
+func WithRateLimiter(limits RateLimits) BotOption {
+	return func(b *Bot) {
+		b.limiter = newRateLimiter(limits, b.clock)
+	}
+}
+
+// chatTargeter es implementado por los payloads que envían mensajes a un chat
+// y por lo tanto están sujetos al rate limiting.
+type chatTargeter interface {
+	targetChatID() int64
+}
+
+func (r SendMessageRequest) targetChatID() int64    { return r.ChatID }
+func (r ForwardMessageRequest) targetChatID() int64 { return r.ChatID }
+func (r CopyMessageRequest) targetChatID() int64    { return r.ChatID }
+func (r SendPhotoRequest) targetChatID() int64      { return r.ChatID }
+func (r SendDocumentRequest) targetChatID() int64   { return r.ChatID }
+func (r SendAudioRequest) targetChatID() int64      { return r.ChatID }
+func (r SendVideoRequest) targetChatID() int64      { return r.ChatID }
+func (r SendVoiceRequest) targetChatID() int64      { return r.ChatID }
+func (r SendAnimationRequest) targetChatID() int64  { return r.ChatID }
+
+// waitRateLimit espera el turno de envío si el payload está dirigido a un chat
+// y el limitador está habilitado.
+func (b *Bot) waitRateLimit(ctx context.Context, payload any) error {
+	if b.limiter == nil {
+		return nil
+	}
+
+	target, ok := payload.(chatTargeter)
+	if !ok {
+		return nil
+	}
+
+	return b.limiter.Wait(ctx, target.targetChatID())
+}
+
+// rateLimiter implementa token buckets para el límite global y por chat.
+// Las reservas se hacen bajo un mutex, por lo que los requests de un mismo
+// chat obtienen turnos en el orden en que llegaron.
+type rateLimiter struct {
+	mu      sync.Mutex
+	clock   clock
+	limits  RateLimits
+	global  bucket
+	chats   map[int64]*bucket
+	pruneAt int
+}
+
+// bucket es un token bucket representado por la hora en la que vuelve a estar
+// lleno (tat). Así se puede consumir un token en un momento futuro, cuando el
+// request realmente se envía, y devolverlo si el envío se cancela.
+type bucket struct {
+	tat     time.Time
+	turns   uint64    // Turnos tomados; identifica el último
+	prevTat time.Time // tat antes del último turno
+}
+
+func newRateLimiter(limits RateLimits, clk clock) *rateLimiter {
+	return &rateLimiter{
+		clock:   clk,
+		limits:  limits,
+		chats:   make(map[int64]*bucket),
+		pruneAt: minChatBuckets,
+	}
+}
+
+// Wait bloquea hasta que se pueda enviar un mensaje al chat indicado. Primero
+// espera el turno del chat y recién entonces toma el turno global, para que
+// los mensajes encolados en un chat no demoren a los demás. Si el contexto se
+// cancela antes, el turno se devuelve para no demorar los envíos siguientes.
+func (l *rateLimiter) Wait(ctx context.Context, chatID int64) error {
+	delay, turn := l.reserve(chatID)
+	if err := l.clock.Sleep(ctx, delay); err != nil {
+		l.release(chatID, turn)
+		return err
+	}
+
+	delay, turn = l.reserveGlobal()
+	if err := l.clock.Sleep(ctx, delay); err != nil {
+		l.releaseGlobal(turn)
+		return err
+	}
+	return nil
+}
+
+// reserve consume un turno del chat y retorna cuánto hay que esperar para
+// usarlo junto con el turno tomado, que se usa para devolverlo.
+func (l *rateLimiter) reserve(chatID int64) (time.Duration, uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if chatID == 0 {
+		return 0, 0
+	}
+
+	now := l.clock.Now()
+	chat, ok := l.chats[chatID]
+	if !ok {
+		l.prune(now)
+		chat = &bucket{}
+		l.chats[chatID] = chat
+	}
+
+	limit := l.chatLimit(chatID)
+	send := chat.earliest(now, limit)
+	return send.Sub(now), chat.take(send, limit)
+}
+
+// reserveGlobal consume un turno del límite global y retorna cuánto hay que
+// esperar para usarlo junto con el turno tomado.
+func (l *rateLimiter) reserveGlobal() (time.Duration, uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	send := l.global.earliest(now, l.limits.Global)
+	return send.Sub(now), l.global.take(send, l.limits.Global)
+}
+
+// release devuelve el turno de chat de un envío cancelado.
+func (l *rateLimiter) release(chatID int64, turn uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.chats[chatID]; ok {
+		b.giveBack(turn, l.chatLimit(chatID))
+	}
+}
+
+// releaseGlobal devuelve el turno global de un envío cancelado.
+func (l *rateLimiter) releaseGlobal(turn uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.global.giveBack(turn, l.limits.Global)
+}
+
+// chatLimit retorna el límite aplicable según el tipo de chat. Los IDs
+// negativos corresponden a grupos, supergrupos y canales.
+func (l *rateLimiter) chatLimit(chatID int64) RateLimit {
+	if chatID < 0 {
+		return l.limits.Group
+	}
+	return l.limits.PrivateChat
+}
+
+// prune elimina los buckets de chats que ya recuperaron todos sus tokens.
+func (l *rateLimiter) prune(now time.Time) {
+	if len(l.chats) < l.pruneAt {
+		return
+	}
+
+	for chatID, b := range l.chats {
+		if b.full(now) {
+			delete(l.chats, chatID)
+		}
+	}
+
+	l.pruneAt = max(minChatBuckets, 2*len(l.chats))
+}
+
+// earliest retorna el primer momento, a partir de at, en el que el bucket
+// tiene un token disponible.
+func (b *bucket) earliest(at time.Time, limit RateLimit) time.Time {
+	if limit.Count <= 0 || limit.Per <= 0 {
+		return at
+	}
+
+	// Con el bucket lleno se admiten Count envíos juntos: el último puede
+	// salir hasta Per - interval antes de que se vacíe por completo
+	interval := limit.Per / time.Duration(limit.Count)
+	return maxTime(at, b.tat.Add(interval-limit.Per))
+}
+
+// take consume un token en el momento send y retorna el turno tomado.
+func (b *bucket) take(send time.Time, limit RateLimit) uint64 {
+	if limit.Count <= 0 || limit.Per <= 0 {
+		return 0
+	}
+
+	interval := limit.Per / time.Duration(limit.Count)
+	b.prevTat = b.tat
+	b.tat = maxTime(b.tat, send).Add(interval)
+	b.turns++
+	return b.turns
+}
+
+// giveBack devuelve un turno tomado con take que no se usó. Solo se puede
+// devolver el último: si hay turnos posteriores, ya están reservados a
+// continuación del cancelado y devolverlo permitiría dos envíos en el mismo
+// turno.
+func (b *bucket) giveBack(turn uint64, limit RateLimit) {
+	if limit.Count <= 0 || limit.Per <= 0 || turn != b.turns {
+		return
+	}
+
+	b.tat = b.prevTat
+}
+
+func (b *bucket) full(now time.Time) bool {
+	return !b.tat.After(now)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
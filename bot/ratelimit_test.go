@@ -0,0 +1,294 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     RateLimits
+		chatIDs    []int64
+		wantDelays []time.Duration
+	}{
+		{
+			name:       "private chat queues one per second",
+			limits:     DefaultRateLimits(),
+			chatIDs:    []int64{1, 1, 1},
+			wantDelays: []time.Duration{0, time.Second, 2 * time.Second},
+		},
+		{
+			name:       "different private chats are independent",
+			limits:     DefaultRateLimits(),
+			chatIDs:    []int64{1, 2, 3},
+			wantDelays: []time.Duration{0, 0, 0},
+		},
+		{
+			name:    "group allows a burst of twenty per minute",
+			limits:  DefaultRateLimits(),
+			chatIDs: []int64{-100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100, -100},
+			wantDelays: []time.Duration{
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+				3 * time.Second, 6 * time.Second,
+			},
+		},
+		{
+			name:       "zero limits disable limiting",
+			limits:     RateLimits{},
+			chatIDs:    []int64{1, 1, 1},
+			wantDelays: []time.Duration{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(tt.limits, newFakeClock())
+
+			for i, chatID := range tt.chatIDs {
+				got, _ := limiter.reserve(chatID)
+				if got.Round(time.Millisecond) != tt.wantDelays[i] {
+					t.Errorf("request %d: expected delay %v, got %v", i, tt.wantDelays[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	clk := newFakeClock()
+	limiter := newRateLimiter(DefaultRateLimits(), clk)
+
+	if d, _ := limiter.reserve(1); d != 0 {
+		t.Fatalf("expected first message without delay, got %v", d)
+	}
+
+	clk.Sleep(context.Background(), time.Second)
+
+	if d, _ := limiter.reserve(1); d != 0 {
+		t.Errorf("expected token to be refilled after 1s, got delay %v", d)
+	}
+}
+
+func TestRateLimiter_reserveGlobal(t *testing.T) {
+	limiter := newRateLimiter(RateLimits{Global: RateLimit{Count: 2, Per: time.Second}}, newFakeClock())
+
+	want := []time.Duration{0, 0, 500 * time.Millisecond, time.Second}
+	for i := range want {
+		if d, _ := limiter.reserveGlobal(); d != want[i] {
+			t.Errorf("request %d: expected delay %v, got %v", i, want[i], d)
+		}
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	clk := newFakeClock()
+	limiter := newRateLimiter(DefaultRateLimits(), clk)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, 42); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Cada envío espera su turno del chat y después el global
+	sleeps := clk.Sleeps()
+	want := []time.Duration{0, 0, time.Second, 0, time.Second, 0}
+	if len(sleeps) != len(want) {
+		t.Fatalf("expected sleeps %v, got %v", want, sleeps)
+	}
+	for i := range want {
+		if sleeps[i] != want[i] {
+			t.Errorf("sleep %d: expected %v, got %v", i, want[i], sleeps[i])
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := limiter.Wait(cancelled, 42); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRateLimiter_GlobalCountsDelayedSends(t *testing.T) {
+	clk := newFakeClock()
+	limiter := newRateLimiter(RateLimits{
+		Global:      RateLimit{Count: 2, Per: time.Second},
+		PrivateChat: RateLimit{Count: 1, Per: time.Second},
+	}, clk)
+
+	// El segundo mensaje al chat 1 sale en 1s: recién ahí consume el turno global
+	for range 2 {
+		if err := limiter.Wait(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := clk.Sleeps(); got[2] != time.Second {
+		t.Fatalf("expected second message to wait for its chat, got sleeps %v", got)
+	}
+
+	want := []time.Duration{0, 500 * time.Millisecond}
+	for i := range want {
+		if d, _ := limiter.reserveGlobal(); d != want[i] {
+			t.Errorf("request %d: expected global delay %v, got %v", i, want[i], d)
+		}
+	}
+}
+
+func TestRateLimiter_QueuedGroupDoesNotDelayOtherChats(t *testing.T) {
+	clk := newFakeClock()
+	limiter := newRateLimiter(DefaultRateLimits(), clk)
+
+	// 40 mensajes encolados para un grupo: los últimos salen en un minuto
+	for range 40 {
+		limiter.reserve(-100)
+	}
+
+	if err := limiter.Wait(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range clk.Sleeps() {
+		if d != 0 {
+			t.Errorf("expected an idle chat to send right away, got sleeps %v", clk.Sleeps())
+			break
+		}
+	}
+}
+
+func TestRateLimiter_Wait_CancelReleasesTurn(t *testing.T) {
+	tests := []struct {
+		name      string
+		chatID    int64
+		sent      int // Mensajes enviados antes de las esperas canceladas
+		wantDelay time.Duration
+	}{
+		{name: "private chat", chatID: 1, sent: 1, wantDelay: time.Second},
+		{name: "group", chatID: -100, sent: 20, wantDelay: 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(DefaultRateLimits(), newFakeClock())
+			for range tt.sent {
+				limiter.reserve(tt.chatID)
+				limiter.reserveGlobal()
+			}
+
+			cancelled, cancel := context.WithCancel(context.Background())
+			cancel()
+			for range 5 {
+				if err := limiter.Wait(cancelled, tt.chatID); !errors.Is(err, context.Canceled) {
+					t.Fatalf("expected context.Canceled, got %v", err)
+				}
+			}
+
+			// Las esperas canceladas no demoran el próximo envío
+			if d, _ := limiter.reserve(tt.chatID); d != tt.wantDelay {
+				t.Errorf("expected delay %v, got %v", tt.wantDelay, d)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_release_MiddleOfQueue(t *testing.T) {
+	limiter := newRateLimiter(DefaultRateLimits(), newFakeClock())
+
+	var turns []uint64
+	for range 3 {
+		_, turn := limiter.reserve(1)
+		turns = append(turns, turn)
+	}
+
+	// El segundo turno (1s) se cancela con el tercero (2s) ya reservado: no
+	// se devuelve, porque el próximo mensaje saldría junto con el tercero
+	limiter.release(1, turns[1])
+	if d, _ := limiter.reserve(1); d != 3*time.Second {
+		t.Errorf("expected next message after the queued ones, got delay %v", d)
+	}
+
+	// El último turno sí se devuelve
+	_, last := limiter.reserve(1)
+	limiter.release(1, last)
+	if d, _ := limiter.reserve(1); d != 4*time.Second {
+		t.Errorf("expected the cancelled last turn to be reused, got delay %v", d)
+	}
+}
+
+func TestRateLimiter_Prune(t *testing.T) {
+	clk := newFakeClock()
+	limiter := newRateLimiter(DefaultRateLimits(), clk)
+
+	for i := int64(1); i <= minChatBuckets; i++ {
+		limiter.reserve(i)
+	}
+
+	clk.Sleep(context.Background(), time.Minute)
+	limiter.reserve(minChatBuckets + 1)
+
+	if len(limiter.chats) != 1 {
+		t.Errorf("expected idle buckets to be pruned, got %d buckets", len(limiter.chats))
+	}
+}
+
+func TestBot_SendMessage_RateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	clk := newFakeClock()
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+		clock:      clk,
+		limiter:    newRateLimiter(DefaultRateLimits(), clk),
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := bot.SendMessage(ctx, 123, "Hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+
+	// Turno del chat y turno global de cada envío
+	sleeps := clk.Sleeps()
+	if len(sleeps) != 4 || sleeps[0] != 0 || sleeps[1] != 0 || sleeps[2] != time.Second || sleeps[3] != 0 {
+		t.Errorf("expected sleeps [0 0 1s 0], got %v", sleeps)
+	}
+
+	// Los métodos que no envían mensajes no pasan por el limitador
+	if _, err := bot.makeRequest(ctx, "getMe", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(clk.Sleeps()) != 4 {
+		t.Errorf("expected getMe not to be rate limited, got sleeps %v", clk.Sleeps())
+	}
+}
+
+func TestBot_WithRateLimiter(t *testing.T) {
+	bot := NewBot("test-token", WithRateLimiter(DefaultRateLimits()))
+
+	if bot.limiter == nil {
+		t.Fatal("expected rate limiter to be set")
+	}
+
+	if NewBot("test-token").limiter != nil {
+		t.Error("expected rate limiting to be disabled by default")
+	}
+}
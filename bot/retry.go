@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+)
+
+// RetryPolicy define cómo se reintentan los requests fallidos a la API.
+//
+// Se reintentan:
+//   - Respuestas 429, esperando el retry_after indicado por Telegram.
+//   - Errores de conexión que impidieron enviar el request.
+//   - Errores de red y respuestas 5xx, solo para métodos idempotentes
+//     (getMe, getUpdates, setWebhook, ...). Un sendMessage que llegó a
+//     Telegram nunca se reintenta para no duplicar el mensaje.
+type RetryPolicy struct {
+	// MaxRetries es la cantidad máxima de reintentos después del primer intento.
+	MaxRetries int
+	// BaseDelay es la espera antes del primer reintento; se duplica en cada intento.
+	BaseDelay time.Duration
+	// MaxDelay es el tope de la espera exponencial.
+	MaxDelay time.Duration
+	// MaxRetryAfter es el máximo retry_after que se está dispuesto a esperar.
+	// Si Telegram pide esperar más, el error se retorna sin reintentar.
+	// Cero significa sin límite.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy retorna una política razonable para la mayoría de los bots.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:    3,
+		BaseDelay:     500 * time.Millisecond,
+		MaxDelay:      30 * time.Second,
+		MaxRetryAfter: time.Minute,
+	}
+}
+
+// WithRetryPolicy habilita los reintentos automáticos de requests fallidos.
+// Por defecto el bot no reintenta.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithRetryPolicy(bot.DefaultRetryPolicy()))
+func WithRetryPolicy(policy RetryPolicy) BotOption {
+	return func(b *Bot) {
+		b.retryPolicy = &policy
+	}
+}
+
+// networkError representa un fallo de transporte al comunicarse con la API.
+type networkError struct {
+	op  string
+	err error
+}
+
+func (e *networkError) Error() string {
+	return fmt.Sprintf("error %s: %v", e.op, e.err)
+}
+
+func (e *networkError) Unwrap() error {
+	return e.err
+}
+
+// sent indica si el request pudo haber llegado a Telegram. Solo los errores
+// al establecer la conexión garantizan que no fue enviado.
+func (e *networkError) sent() bool {
+	var opErr *net.OpError
+	if errors.As(e.err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	return !errors.As(e.err, &dnsErr)
+}
+
+// shouldRetry decide si el error del intento attempt (empezando en 0) debe
+// reintentarse y cuánto esperar antes de hacerlo.
+func (p *RetryPolicy) shouldRetry(method string, attempt int, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxRetries {
+		return 0, false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case errors.Is(apiErr, ErrTooManyRequests):
+			retryAfter := apiErr.RetryAfter()
+			if retryAfter == 0 {
+				return p.backoff(attempt), true
+			}
+			if p.MaxRetryAfter > 0 && retryAfter > p.MaxRetryAfter {
+				return 0, false
+			}
+			return retryAfter, true
+		case errors.Is(apiErr, ErrServerError):
+			return p.backoff(attempt), isIdempotentMethod(method)
+		}
+		return 0, false
+	}
+
+	var netErr *networkError
+	if errors.As(err, &netErr) {
+		return p.backoff(attempt), !netErr.sent() || isIdempotentMethod(method)
+	}
+
+	return 0, false
+}
+
+// backoff calcula la espera exponencial con jitter para el intento dado.
+// La espera resultante está entre la mitad y el total del valor exponencial.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half+1)
+}
+
+// isIdempotentMethod indica si repetir el método no tiene efectos secundarios.
+func isIdempotentMethod(method string) bool {
+	if strings.HasPrefix(method, "get") {
+		return true
+	}
+
+	switch method {
+	case "setWebhook", "deleteWebhook",
+		"editMessageText", "editMessageReplyMarkup",
+		"deleteMessage", "deleteMessages",
+		"setMyCommands", "deleteMyCommands":
+		return true
+	}
+
+	return false
+}
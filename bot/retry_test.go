@@ -0,0 +1,329 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock es un reloj manual: Sleep avanza el tiempo sin bloquear.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	return nil
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+func TestBot_makeRequest_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		responses    []string
+		statuses     []int
+		wantErr      bool
+		wantRequests int
+		wantSleeps   int
+	}{
+		{
+			name:         "429 honours retry_after",
+			method:       "sendMessage",
+			responses:    []string{`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 2","parameters":{"retry_after":2}}`, `{"ok":true,"result":{"message_id":1}}`},
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			wantRequests: 2,
+			wantSleeps:   1,
+		},
+		{
+			name:         "5xx retried for idempotent method",
+			method:       "getMe",
+			responses:    []string{`{"ok":false,"error_code":502,"description":"Bad Gateway"}`, ``, `{"ok":true,"result":{"id":1}}`},
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantRequests: 3,
+			wantSleeps:   2,
+		},
+		{
+			name:         "5xx not retried for non-idempotent method",
+			method:       "sendMessage",
+			responses:    []string{`{"ok":false,"error_code":500,"description":"Internal Server Error"}`},
+			statuses:     []int{http.StatusInternalServerError},
+			wantErr:      true,
+			wantRequests: 1,
+			wantSleeps:   0,
+		},
+		{
+			name:         "4xx never retried",
+			method:       "getMe",
+			responses:    []string{`{"ok":false,"error_code":401,"description":"Unauthorized"}`},
+			statuses:     []int{http.StatusUnauthorized},
+			wantErr:      true,
+			wantRequests: 1,
+			wantSleeps:   0,
+		},
+		{
+			name:   "gives up after max retries",
+			method: "getMe",
+			responses: []string{
+				`{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+				`{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+				`{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+				`{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+			},
+			statuses:     []int{500, 500, 500, 500},
+			wantErr:      true,
+			wantRequests: 4,
+			wantSleeps:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := requests
+				requests++
+				w.WriteHeader(tt.statuses[i])
+				w.Write([]byte(tt.responses[i]))
+			}))
+			defer server.Close()
+
+			clk := newFakeClock()
+			policy := DefaultRetryPolicy()
+			bot := &Bot{
+				token:       "test-token",
+				client:      &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL:  server.URL + "/bot%s/%s",
+				logger:      testLogger(),
+				retryPolicy: &policy,
+				clock:       clk,
+			}
+
+			_, err := bot.makeRequest(context.Background(), tt.method, nil)
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if requests != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, requests)
+			}
+
+			if got := len(clk.Sleeps()); got != tt.wantSleeps {
+				t.Errorf("expected %d sleeps, got %d", tt.wantSleeps, got)
+			}
+		})
+	}
+}
+
+func TestBot_makeRequest_RetryAfterDelay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	clk := newFakeClock()
+	policy := DefaultRetryPolicy()
+	bot := &Bot{
+		token:       "test-token",
+		client:      &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL:  server.URL + "/bot%s/%s",
+		logger:      testLogger(),
+		retryPolicy: &policy,
+		clock:       clk,
+	}
+
+	if _, err := bot.makeRequest(context.Background(), "sendMessage", SendMessageRequest{ChatID: 1, Text: "hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sleeps := clk.Sleeps()
+	if len(sleeps) != 1 || sleeps[0] != 5*time.Second {
+		t.Errorf("expected a single 5s sleep, got %v", sleeps)
+	}
+}
+
+func TestBot_makeRequest_RetryAfterTooLong(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 300","parameters":{"retry_after":300}}`))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	bot := &Bot{
+		token:       "test-token",
+		client:      &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL:  server.URL + "/bot%s/%s",
+		logger:      testLogger(),
+		retryPolicy: &policy,
+		clock:       newFakeClock(),
+	}
+
+	_, err := bot.makeRequest(context.Background(), "sendMessage", nil)
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("expected ErrTooManyRequests, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestBot_makeRequest_RetryNetworkErrors(t *testing.T) {
+	t.Run("connection refused is retried for any method", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		clk := newFakeClock()
+		policy := RetryPolicy{MaxRetries: 2, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+		bot := &Bot{
+			token:       "test-token",
+			client:      &http.Client{Timeout: 5 * time.Second},
+			apiBaseURL:  url + "/bot%s/%s",
+			logger:      testLogger(),
+			retryPolicy: &policy,
+			clock:       clk,
+		}
+
+		_, err := bot.makeRequest(context.Background(), "sendMessage", nil)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		sleeps := clk.Sleeps()
+		if len(sleeps) != 2 {
+			t.Fatalf("expected 2 sleeps, got %v", sleeps)
+		}
+
+		// Backoff exponencial con jitter: [0.5s, 1s] y [1s, 2s]
+		if sleeps[0] < 500*time.Millisecond || sleeps[0] > time.Second {
+			t.Errorf("first backoff out of range: %v", sleeps[0])
+		}
+		if sleeps[1] < time.Second || sleeps[1] > 2*time.Second {
+			t.Errorf("second backoff out of range: %v", sleeps[1])
+		}
+	})
+
+	t.Run("dropped connection is not retried for sendMessage", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		}))
+		defer server.Close()
+
+		policy := DefaultRetryPolicy()
+		bot := &Bot{
+			token:       "test-token",
+			client:      &http.Client{Timeout: 5 * time.Second},
+			apiBaseURL:  server.URL + "/bot%s/%s",
+			logger:      testLogger(),
+			retryPolicy: &policy,
+			clock:       newFakeClock(),
+		}
+
+		if _, err := bot.makeRequest(context.Background(), "sendMessage", nil); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if got := requests.Load(); got != 1 {
+			t.Errorf("expected 1 request, got %d", got)
+		}
+	})
+}
+
+func TestBot_makeRequest_RetryContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel()
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	bot := &Bot{
+		token:       "test-token",
+		client:      &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL:  server.URL + "/bot%s/%s",
+		logger:      testLogger(),
+		retryPolicy: &policy,
+		clock:       newFakeClock(),
+	}
+
+	_, err := bot.makeRequest(ctx, "sendMessage", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestBot_WithRetryPolicy(t *testing.T) {
+	bot := NewBot("test-token", WithRetryPolicy(DefaultRetryPolicy()))
+
+	if bot.retryPolicy == nil {
+		t.Fatal("expected retry policy to be set")
+	}
+
+	if bot.retryPolicy.MaxRetries != 3 {
+		t.Errorf("expected 3 max retries, got %d", bot.retryPolicy.MaxRetries)
+	}
+
+	if NewBot("test-token").retryPolicy != nil {
+		t.Error("expected retries to be disabled by default")
+	}
+}
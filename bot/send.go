@@ -0,0 +1,95 @@
+package bot
+
+// Modos de formato para el texto de los mensajes.
+const (
+	ParseModeHTML       = "HTML"
+	ParseModeMarkdownV2 = "MarkdownV2"
+	ParseModeMarkdown   = "Markdown" // Modo legado, se recomienda MarkdownV2
+)
+
+// SendOption configura un parámetro opcional de los métodos de envío.
+//
+// Ejemplo:
+//
+//	_, err := b.SendMessage(ctx, chatID, "<b>Listo</b>",
+//	    bot.WithParseMode(bot.ParseModeHTML),
+//	    bot.WithReplyTo(msg.MessageID),
+//	    bot.WithDisableNotification(),
+//	)
+type SendOption func(*SendOptions)
+
+// WithParseMode configura el modo de formato del texto (ParseModeHTML,
+// ParseModeMarkdownV2 o ParseModeMarkdown).
+func WithParseMode(mode string) SendOption {
+	return func(o *SendOptions) {
+		o.ParseMode = mode
+	}
+}
+
+// WithReplyTo envía el mensaje como respuesta al mensaje indicado del mismo chat.
+// Si el mensaje original ya no existe, el envío continúa sin la respuesta.
+func WithReplyTo(messageID int) SendOption {
+	return func(o *SendOptions) {
+		o.ReplyParameters = &ReplyParameters{
+			MessageID:                messageID,
+			AllowSendingWithoutReply: true,
+		}
+	}
+}
+
+// WithReplyParameters configura la respuesta con todos sus parámetros, por
+// ejemplo para responder a un mensaje de otro chat o citar parte del texto.
+func WithReplyParameters(params ReplyParameters) SendOption {
+	return func(o *SendOptions) {
+		o.ReplyParameters = &params
+	}
+}
+
+// WithDisableNotification envía el mensaje de forma silenciosa: los usuarios
+// lo reciben sin sonido.
+func WithDisableNotification() SendOption {
+	return func(o *SendOptions) {
+		o.DisableNotification = true
+	}
+}
+
+// WithProtectContent impide que el mensaje sea reenviado o guardado.
+func WithProtectContent() SendOption {
+	return func(o *SendOptions) {
+		o.ProtectContent = true
+	}
+}
+
+// WithLinkPreviewOptions configura la vista previa de los enlaces del texto.
+// Solo aplica a mensajes de texto.
+func WithLinkPreviewOptions(opts LinkPreviewOptions) SendOption {
+	return func(o *SendOptions) {
+		o.LinkPreviewOptions = &opts
+	}
+}
+
+// WithoutLinkPreview deshabilita la vista previa de los enlaces del texto.
+func WithoutLinkPreview() SendOption {
+	return WithLinkPreviewOptions(LinkPreviewOptions{IsDisabled: true})
+}
+
+// WithMessageThreadID envía el mensaje a un tema (topic) de un foro.
+func WithMessageThreadID(threadID int) SendOption {
+	return func(o *SendOptions) {
+		o.MessageThreadID = threadID
+	}
+}
+
+// WithReplyMarkup adjunta un teclado al mensaje enviado.
+func WithReplyMarkup(markup ReplyMarkup) SendOption {
+	return func(o *SendOptions) {
+		o.ReplyMarkup = markup
+	}
+}
+
+// apply aplica las opciones sobre los parámetros del envío.
+func (o *SendOptions) apply(opts []SendOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBot_SendMessage_Options(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []SendOption
+		want map[string]string
+	}{
+		{
+			name: "no options",
+			opts: nil,
+			want: map[string]string{
+				"chat_id": `123`,
+				"text":    `"Hello"`,
+			},
+		},
+		{
+			name: "parse mode",
+			opts: []SendOption{WithParseMode(ParseModeHTML)},
+			want: map[string]string{
+				"parse_mode": `"HTML"`,
+			},
+		},
+		{
+			name: "reply to message",
+			opts: []SendOption{WithReplyTo(10)},
+			want: map[string]string{
+				"reply_parameters": `{"message_id":10,"allow_sending_without_reply":true}`,
+			},
+		},
+		{
+			name: "reply parameters",
+			opts: []SendOption{WithReplyParameters(ReplyParameters{MessageID: 5, ChatID: -100, Quote: "hola"})},
+			want: map[string]string{
+				"reply_parameters": `{"message_id":5,"chat_id":-100,"quote":"hola"}`,
+			},
+		},
+		{
+			name: "silent and protected",
+			opts: []SendOption{WithDisableNotification(), WithProtectContent()},
+			want: map[string]string{
+				"disable_notification": `true`,
+				"protect_content":      `true`,
+			},
+		},
+		{
+			name: "without link preview",
+			opts: []SendOption{WithoutLinkPreview()},
+			want: map[string]string{
+				"link_preview_options": `{"is_disabled":true}`,
+			},
+		},
+		{
+			name: "link preview options",
+			opts: []SendOption{WithLinkPreviewOptions(LinkPreviewOptions{URL: "https://example.com", PreferLargeMedia: true})},
+			want: map[string]string{
+				"link_preview_options": `{"url":"https://example.com","prefer_large_media":true}`,
+			},
+		},
+		{
+			name: "message thread",
+			opts: []SendOption{WithMessageThreadID(7)},
+			want: map[string]string{
+				"message_thread_id": `7`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received map[string]json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+			}))
+			defer server.Close()
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			if _, err := bot.SendMessage(context.Background(), 123, "Hello", tt.opts...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for field, want := range tt.want {
+				if got := string(received[field]); got != want {
+					t.Errorf("expected %s=%s, got %s", field, want, got)
+				}
+			}
+
+			// Los campos no configurados no deben enviarse
+			if len(tt.opts) == 0 && len(received) != 2 {
+				t.Errorf("expected only chat_id and text, got %v", received)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// DefaultShutdownGracePeriod es el tiempo que Start espera por defecto a
+	// que terminen los handlers en curso al detenerse.
+	DefaultShutdownGracePeriod = 10 * time.Second
+
+	// confirmOffsetTimeout limita el request que confirma el offset final.
+	confirmOffsetTimeout = 5 * time.Second
+)
+
+// WithShutdownGracePeriod configura cuánto espera Start, al cancelarse su
+// contexto, a que terminen los handlers en curso y los updates en cola.
+// Pasado ese tiempo se cancela el contexto de los handlers y los updates que
+// no se procesaron se vuelven a recibir en el próximo inicio. Con cero no se
+// espera.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithShutdownGracePeriod(30*time.Second))
+func WithShutdownGracePeriod(d time.Duration) BotOption {
+	return func(b *Bot) {
+		b.gracePeriod = d
+	}
+}
+
+// shutdown espera a que el dispatcher procese las actualizaciones pendientes
+// durante el período de gracia, y guarda y confirma a Telegram el offset de
+// las que terminaron.
+func (b *Bot) shutdown(d *dispatcher, cancelHandlers context.CancelFunc) {
+	drained := make(chan struct{})
+	go func() {
+		d.wait()
+		close(drained)
+	}()
+
+	timer := time.NewTimer(b.gracePeriod)
+	defer timer.Stop()
+
+	offset := b.offset
+	select {
+	case <-drained:
+	case <-timer.C:
+		d.abort()
+		// Los updates en proceso o en cola no terminaron: se confirma el offset
+		// hasta el primero de ellos para volver a recibirlos al reiniciar
+		if first, ok := d.firstUnfinished(); ok {
+			offset = first
+		}
+		cancelHandlers()
+
+		b.logger.Warn("Período de gracia agotado, cancelando handlers en curso",
+			slog.Duration("grace_period", b.gracePeriod),
+			slog.Int("offset", offset),
+		)
+	}
+
+	if offset == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), confirmOffsetTimeout)
+	defer cancel()
+
+	b.saveOffset(ctx, offset)
+	if err := b.confirmOffset(ctx, offset); err != nil {
+		b.logger.Error("Error confirmando offset final",
+			slog.Int("offset", offset),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	b.logger.Info("Offset final confirmado", slog.Int("offset", offset))
+}
+
+// confirmOffset confirma a Telegram las actualizaciones anteriores a offset
+// para que no se vuelvan a entregar. Telegram las confirma al recibir un
+// getUpdates con ese offset; las que retorna se ignoran y se vuelven a
+// recibir en el próximo inicio.
+func (b *Bot) confirmOffset(ctx context.Context, offset int) error {
+	params := map[string]int{
+		"offset":  offset,
+		"timeout": 0,
+		"limit":   1,
+	}
+
+	_, err := b.makeRequest(ctx, "getUpdates", params)
+	return err
+}
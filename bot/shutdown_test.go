@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pollingServer simula getUpdates: entrega batch en el primer long polling,
+// mantiene abiertos los siguientes hasta que el cliente los cancele y guarda
+// los offsets pedidos en cada long polling y los confirmados con timeout 0.
+type pollingServer struct {
+	batch string
+
+	mu        sync.Mutex
+	polls     []int
+	confirmed []int
+	sent      []string
+}
+
+func (s *pollingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Offset  int    `json:"offset"`
+		Timeout int    `json:"timeout"`
+		Text    string `json:"text"`
+	}
+	json.NewDecoder(r.Body).Decode(&params)
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.mu.Lock()
+	switch {
+	case method == "getMe":
+		s.mu.Unlock()
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Bot","username":"testbot"}}`))
+		return
+	case method == "sendMessage":
+		s.sent = append(s.sent, params.Text)
+		s.mu.Unlock()
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+		return
+	case params.Timeout == 0:
+		s.confirmed = append(s.confirmed, params.Offset)
+		s.mu.Unlock()
+		w.Write([]byte(`{"ok":true,"result":[]}`))
+		return
+	}
+	s.polls = append(s.polls, params.Offset)
+	first := len(s.polls) == 1
+	s.mu.Unlock()
+
+	if first {
+		w.Write([]byte(`{"ok":true,"result":[` + s.batch + `]}`))
+		return
+	}
+	<-r.Context().Done()
+}
+
+// firstPoll espera el primer long polling y retorna el offset pedido.
+func (s *pollingServer) firstPoll(t *testing.T) int {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		if len(s.polls) > 0 {
+			offset := s.polls[0]
+			s.mu.Unlock()
+			return offset
+		}
+		s.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("getUpdates was never called")
+	return 0
+}
+
+func (s *pollingServer) snapshot() (confirmed []int, sent []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.confirmed...), append([]string(nil), s.sent...)
+}
+
+const shutdownBatch = `{"update_id":1,"message":{"message_id":1,"chat":{"id":1},"from":{"id":1,"first_name":"A"},"text":"uno"}},` +
+	`{"update_id":2,"message":{"message_id":2,"chat":{"id":1},"from":{"id":1,"first_name":"A"},"text":"dos"}}`
+
+func TestBot_Start_DrainsInFlightHandlers(t *testing.T) {
+	fake := &pollingServer{batch: shutdownBatch}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	started := make(chan struct{}, 2)
+	proceed := make(chan struct{})
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+			started <- struct{}{}
+			<-proceed
+			if _, err := b.SendMessage(ctx, msg.Chat.ID, "respuesta "+msg.Text); err != nil {
+				t.Errorf("expected reply to be sent during shutdown, got %v", err)
+			}
+			return nil
+		}),
+	)
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Start(ctx) }()
+
+	<-started
+	cancel()
+
+	// Start no debe retornar mientras haya handlers en curso
+	select {
+	case <-done:
+		t.Fatal("Start returned before in-flight handlers finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(proceed)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after handlers finished")
+	}
+
+	confirmed, sent := fake.snapshot()
+	if want := []string{"respuesta uno", "respuesta dos"}; strings.Join(sent, ",") != strings.Join(want, ",") {
+		t.Errorf("expected replies %v, got %v", want, sent)
+	}
+	if len(confirmed) != 1 || confirmed[0] != 3 {
+		t.Errorf("expected final offset 3 to be confirmed, got %v", confirmed)
+	}
+}
+
+func TestBot_Start_GracePeriodExpires(t *testing.T) {
+	fake := &pollingServer{batch: shutdownBatch}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	started := make(chan struct{}, 2)
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+	bot := NewBot("test-token",
+		WithLogger(testLogger()),
+		WithShutdownGracePeriod(50*time.Millisecond),
+		OnMessage(func(ctx context.Context, b *Bot, msg *Message) error {
+			started <- struct{}{}
+			<-ctx.Done() // Un handler que no termina hasta que lo cancelan
+
+			mu.Lock()
+			handled = append(handled, msg.Text)
+			mu.Unlock()
+			return nil
+		}),
+	)
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Start(ctx) }()
+
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after the grace period")
+	}
+
+	// El update 2 estaba en cola detrás del 1, en el mismo chat: no se procesa
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != "uno" {
+		t.Errorf("expected only the in-flight update to run, got %v", handled)
+	}
+
+	// Ninguno terminó a tiempo: ambos se vuelven a recibir al reiniciar
+	confirmed, _ := fake.snapshot()
+	if len(confirmed) != 1 || confirmed[0] != 1 {
+		t.Errorf("expected offset 1 to be confirmed, got %v", confirmed)
+	}
+}
@@ -6,40 +6,664 @@ import (
 
 type (
 	Update struct {
-		UpdateID int      `json:"update_id"`
-		Message  *Message `json:"message,omitempty"`
+		UpdateID                int                          `json:"update_id"`
+		Message                 *Message                     `json:"message,omitempty"`
+		EditedMessage           *Message                     `json:"edited_message,omitempty"`
+		ChannelPost             *Message                     `json:"channel_post,omitempty"`
+		EditedChannelPost       *Message                     `json:"edited_channel_post,omitempty"`
+		BusinessConnection      *BusinessConnection          `json:"business_connection,omitempty"`
+		BusinessMessage         *Message                     `json:"business_message,omitempty"`
+		EditedBusinessMessage   *Message                     `json:"edited_business_message,omitempty"`
+		DeletedBusinessMessages *BusinessMessagesDeleted     `json:"deleted_business_messages,omitempty"`
+		MessageReaction         *MessageReactionUpdated      `json:"message_reaction,omitempty"`
+		MessageReactionCount    *MessageReactionCountUpdated `json:"message_reaction_count,omitempty"`
+		InlineQuery             *InlineQuery                 `json:"inline_query,omitempty"`
+		ChosenInlineResult      *ChosenInlineResult          `json:"chosen_inline_result,omitempty"`
+		CallbackQuery           *CallbackQuery               `json:"callback_query,omitempty"`
+		ShippingQuery           *ShippingQuery               `json:"shipping_query,omitempty"`
+		PreCheckoutQuery        *PreCheckoutQuery            `json:"pre_checkout_query,omitempty"`
+		PurchasedPaidMedia      *PaidMediaPurchased          `json:"purchased_paid_media,omitempty"`
+		Poll                    *Poll                        `json:"poll,omitempty"`
+		PollAnswer              *PollAnswer                  `json:"poll_answer,omitempty"`
+		MyChatMember            *ChatMemberUpdated           `json:"my_chat_member,omitempty"`
+		ChatMember              *ChatMemberUpdated           `json:"chat_member,omitempty"`
+		ChatJoinRequest         *ChatJoinRequest             `json:"chat_join_request,omitempty"`
+		ChatBoost               *ChatBoostUpdated            `json:"chat_boost,omitempty"`
+		RemovedChatBoost        *ChatBoostRemoved            `json:"removed_chat_boost,omitempty"`
+
+		raw json.RawMessage // JSON recibido de Telegram, ver decodeUpdate
 	}
 
+	// Message representa un mensaje. Los campos de contenido son excluyentes
+	// según el tipo de mensaje: un mensaje con foto tiene Photo y, opcionalmente,
+	// Caption, pero no Text. Los mensajes de servicio (miembros que ingresan o
+	// salen, mensajes fijados, migraciones) no tienen contenido propio.
 	Message struct {
-		MessageID int    `json:"message_id"`
-		From      *User  `json:"from,omitempty"`
-		Chat      *Chat  `json:"chat"`
-		Date      int64  `json:"date"`
-		Text      string `json:"text,omitempty"`
+		MessageID         int                   `json:"message_id"`
+		MessageThreadID   int                   `json:"message_thread_id,omitempty"`
+		From              *User                 `json:"from,omitempty"`
+		SenderChat        *Chat                 `json:"sender_chat,omitempty"`
+		Date              int64                 `json:"date"`
+		Chat              *Chat                 `json:"chat"`
+		ForwardOrigin     *MessageOrigin        `json:"forward_origin,omitempty"`
+		IsTopicMessage    bool                  `json:"is_topic_message,omitempty"`
+		ReplyToMessage    *Message              `json:"reply_to_message,omitempty"`
+		EditDate          int64                 `json:"edit_date,omitempty"`
+		MediaGroupID      string                `json:"media_group_id,omitempty"`
+		Text              string                `json:"text,omitempty"`
+		Entities          []MessageEntity       `json:"entities,omitempty"`
+		Animation         *Animation            `json:"animation,omitempty"`
+		Audio             *Audio                `json:"audio,omitempty"`
+		Document          *Document             `json:"document,omitempty"`
+		Photo             []PhotoSize           `json:"photo,omitempty"`
+		Sticker           *Sticker              `json:"sticker,omitempty"`
+		Video             *Video                `json:"video,omitempty"`
+		Voice             *Voice                `json:"voice,omitempty"`
+		Caption           string                `json:"caption,omitempty"`
+		CaptionEntities   []MessageEntity       `json:"caption_entities,omitempty"`
+		Contact           *Contact              `json:"contact,omitempty"`
+		Location          *Location             `json:"location,omitempty"`
+		NewChatMembers    []User                `json:"new_chat_members,omitempty"`
+		LeftChatMember    *User                 `json:"left_chat_member,omitempty"`
+		NewChatTitle      string                `json:"new_chat_title,omitempty"`
+		PinnedMessage     *Message              `json:"pinned_message,omitempty"`
+		MigrateToChatID   int64                 `json:"migrate_to_chat_id,omitempty"`
+		MigrateFromChatID int64                 `json:"migrate_from_chat_id,omitempty"`
+		ReplyMarkup       *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	}
+
+	// MessageEntity marca una parte del texto o caption: comandos, menciones,
+	// URLs o formato. Offset y Length se miden en unidades UTF-16.
+	MessageEntity struct {
+		Type          string `json:"type"` // "mention", "bot_command", "url", "bold", "text_link", ...
+		Offset        int    `json:"offset"`
+		Length        int    `json:"length"`
+		URL           string `json:"url,omitempty"`
+		User          *User  `json:"user,omitempty"`
+		Language      string `json:"language,omitempty"`
+		CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+	}
+
+	// MessageOrigin agrupa los campos de todos los orígenes de un mensaje
+	// reenviado. Type indica la variante: "user", "hidden_user", "chat" o
+	// "channel".
+	MessageOrigin struct {
+		Type            string `json:"type"`
+		Date            int64  `json:"date"`
+		SenderUser      *User  `json:"sender_user,omitempty"`
+		SenderUserName  string `json:"sender_user_name,omitempty"`
+		SenderChat      *Chat  `json:"sender_chat,omitempty"`
+		Chat            *Chat  `json:"chat,omitempty"`
+		MessageID       int    `json:"message_id,omitempty"`
+		AuthorSignature string `json:"author_signature,omitempty"`
+	}
+
+	// PhotoSize es una de las resoluciones de una foto. Message.Photo incluye
+	// todas las disponibles, ordenadas de menor a mayor.
+	PhotoSize struct {
+		FileID       string `json:"file_id"`
+		FileUniqueID string `json:"file_unique_id"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		FileSize     int64  `json:"file_size,omitempty"`
+	}
+
+	Animation struct {
+		FileID       string     `json:"file_id"`
+		FileUniqueID string     `json:"file_unique_id"`
+		Width        int        `json:"width"`
+		Height       int        `json:"height"`
+		Duration     int        `json:"duration"`
+		Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+		FileName     string     `json:"file_name,omitempty"`
+		MimeType     string     `json:"mime_type,omitempty"`
+		FileSize     int64      `json:"file_size,omitempty"`
+	}
+
+	Audio struct {
+		FileID       string     `json:"file_id"`
+		FileUniqueID string     `json:"file_unique_id"`
+		Duration     int        `json:"duration"`
+		Performer    string     `json:"performer,omitempty"`
+		Title        string     `json:"title,omitempty"`
+		FileName     string     `json:"file_name,omitempty"`
+		MimeType     string     `json:"mime_type,omitempty"`
+		FileSize     int64      `json:"file_size,omitempty"`
+		Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+	}
+
+	Document struct {
+		FileID       string     `json:"file_id"`
+		FileUniqueID string     `json:"file_unique_id"`
+		Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+		FileName     string     `json:"file_name,omitempty"`
+		MimeType     string     `json:"mime_type,omitempty"`
+		FileSize     int64      `json:"file_size,omitempty"`
+	}
+
+	Sticker struct {
+		FileID       string     `json:"file_id"`
+		FileUniqueID string     `json:"file_unique_id"`
+		Type         string     `json:"type"` // "regular", "mask", "custom_emoji"
+		Width        int        `json:"width"`
+		Height       int        `json:"height"`
+		IsAnimated   bool       `json:"is_animated"`
+		IsVideo      bool       `json:"is_video"`
+		Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+		Emoji        string     `json:"emoji,omitempty"`
+		SetName      string     `json:"set_name,omitempty"`
+		FileSize     int64      `json:"file_size,omitempty"`
+	}
+
+	Video struct {
+		FileID       string     `json:"file_id"`
+		FileUniqueID string     `json:"file_unique_id"`
+		Width        int        `json:"width"`
+		Height       int        `json:"height"`
+		Duration     int        `json:"duration"`
+		Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+		FileName     string     `json:"file_name,omitempty"`
+		MimeType     string     `json:"mime_type,omitempty"`
+		FileSize     int64      `json:"file_size,omitempty"`
+	}
+
+	Voice struct {
+		FileID       string `json:"file_id"`
+		FileUniqueID string `json:"file_unique_id"`
+		Duration     int    `json:"duration"`
+		MimeType     string `json:"mime_type,omitempty"`
+		FileSize     int64  `json:"file_size,omitempty"`
+	}
+
+	Contact struct {
+		PhoneNumber string `json:"phone_number"`
+		FirstName   string `json:"first_name"`
+		LastName    string `json:"last_name,omitempty"`
+		UserID      int64  `json:"user_id,omitempty"`
+		VCard       string `json:"vcard,omitempty"`
+	}
+
+	Location struct {
+		Latitude             float64 `json:"latitude"`
+		Longitude            float64 `json:"longitude"`
+		HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+		LivePeriod           int     `json:"live_period,omitempty"`
+		Heading              int     `json:"heading,omitempty"`
+		ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
 	}
 
 	User struct {
+		ID           int64  `json:"id"`
+		IsBot        bool   `json:"is_bot"`
+		FirstName    string `json:"first_name"`
+		LastName     string `json:"last_name,omitempty"`
+		Username     string `json:"username,omitempty"`
+		LanguageCode string `json:"language_code,omitempty"`
+
+		// Campos que solo se informan para el propio bot en GetMe
+		CanJoinGroups           bool `json:"can_join_groups,omitempty"`
+		CanReadAllGroupMessages bool `json:"can_read_all_group_messages,omitempty"`
+		SupportsInlineQueries   bool `json:"supports_inline_queries,omitempty"`
+		CanConnectToBusiness    bool `json:"can_connect_to_business,omitempty"`
+		HasMainWebApp           bool `json:"has_main_web_app,omitempty"`
+	}
+
+	// File representa un archivo listo para descargar con DownloadFile.
+	File struct {
+		FileID       string `json:"file_id"`
+		FileUniqueID string `json:"file_unique_id"`
+		FileSize     int64  `json:"file_size,omitempty"`
+		FilePath     string `json:"file_path,omitempty"`
+	}
+
+	Chat struct {
 		ID        int64  `json:"id"`
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name,omitempty"`
+		Type      string `json:"type"` // "private", "group", "supergroup", "channel"
+		Title     string `json:"title,omitempty"`
 		Username  string `json:"username,omitempty"`
+		FirstName string `json:"first_name,omitempty"`
+		LastName  string `json:"last_name,omitempty"`
+		IsForum   bool   `json:"is_forum,omitempty"`
 	}
 
-	Chat struct {
-		ID       int64  `json:"id"`
-		Type     string `json:"type"`
-		Title    string `json:"title,omitempty"`
-		Username string `json:"username,omitempty"`
+	BusinessConnection struct {
+		ID         string `json:"id"`
+		User       *User  `json:"user"`
+		UserChatID int64  `json:"user_chat_id"`
+		Date       int64  `json:"date"`
+		IsEnabled  bool   `json:"is_enabled"`
+	}
+
+	BusinessMessagesDeleted struct {
+		BusinessConnectionID string `json:"business_connection_id"`
+		Chat                 *Chat  `json:"chat"`
+		MessageIDs           []int  `json:"message_ids"`
+	}
+
+	ReactionType struct {
+		Type          string `json:"type"` // "emoji", "custom_emoji", "paid"
+		Emoji         string `json:"emoji,omitempty"`
+		CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+	}
+
+	ReactionCount struct {
+		Type       ReactionType `json:"type"`
+		TotalCount int          `json:"total_count"`
+	}
+
+	MessageReactionUpdated struct {
+		Chat        *Chat          `json:"chat"`
+		MessageID   int            `json:"message_id"`
+		User        *User          `json:"user,omitempty"`
+		ActorChat   *Chat          `json:"actor_chat,omitempty"`
+		Date        int64          `json:"date"`
+		OldReaction []ReactionType `json:"old_reaction"`
+		NewReaction []ReactionType `json:"new_reaction"`
+	}
+
+	MessageReactionCountUpdated struct {
+		Chat      *Chat           `json:"chat"`
+		MessageID int             `json:"message_id"`
+		Date      int64           `json:"date"`
+		Reactions []ReactionCount `json:"reactions"`
+	}
+
+	InlineQuery struct {
+		ID       string `json:"id"`
+		From     *User  `json:"from"`
+		Query    string `json:"query"`
+		Offset   string `json:"offset"`
+		ChatType string `json:"chat_type,omitempty"`
+	}
+
+	ChosenInlineResult struct {
+		ResultID        string `json:"result_id"`
+		From            *User  `json:"from"`
+		InlineMessageID string `json:"inline_message_id,omitempty"`
+		Query           string `json:"query"`
+	}
+
+	CallbackQuery struct {
+		ID              string   `json:"id"`
+		From            *User    `json:"from"`
+		Message         *Message `json:"message,omitempty"`
+		InlineMessageID string   `json:"inline_message_id,omitempty"`
+		ChatInstance    string   `json:"chat_instance"`
+		Data            string   `json:"data,omitempty"`
+		GameShortName   string   `json:"game_short_name,omitempty"`
+	}
+
+	ShippingAddress struct {
+		CountryCode string `json:"country_code"`
+		State       string `json:"state"`
+		City        string `json:"city"`
+		StreetLine1 string `json:"street_line1"`
+		StreetLine2 string `json:"street_line2"`
+		PostCode    string `json:"post_code"`
+	}
+
+	OrderInfo struct {
+		Name            string           `json:"name,omitempty"`
+		PhoneNumber     string           `json:"phone_number,omitempty"`
+		Email           string           `json:"email,omitempty"`
+		ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	}
+
+	ShippingQuery struct {
+		ID              string           `json:"id"`
+		From            *User            `json:"from"`
+		InvoicePayload  string           `json:"invoice_payload"`
+		ShippingAddress *ShippingAddress `json:"shipping_address"`
+	}
+
+	PreCheckoutQuery struct {
+		ID               string     `json:"id"`
+		From             *User      `json:"from"`
+		Currency         string     `json:"currency"`
+		TotalAmount      int        `json:"total_amount"`
+		InvoicePayload   string     `json:"invoice_payload"`
+		ShippingOptionID string     `json:"shipping_option_id,omitempty"`
+		OrderInfo        *OrderInfo `json:"order_info,omitempty"`
+	}
+
+	PaidMediaPurchased struct {
+		From             *User  `json:"from"`
+		PaidMediaPayload string `json:"paid_media_payload"`
+	}
+
+	PollOption struct {
+		Text       string `json:"text"`
+		VoterCount int    `json:"voter_count"`
+	}
+
+	Poll struct {
+		ID                    string       `json:"id"`
+		Question              string       `json:"question"`
+		Options               []PollOption `json:"options"`
+		TotalVoterCount       int          `json:"total_voter_count"`
+		IsClosed              bool         `json:"is_closed"`
+		IsAnonymous           bool         `json:"is_anonymous"`
+		Type                  string       `json:"type"` // "regular", "quiz"
+		AllowsMultipleAnswers bool         `json:"allows_multiple_answers"`
+		CorrectOptionID       *int         `json:"correct_option_id,omitempty"`
+		Explanation           string       `json:"explanation,omitempty"`
+		OpenPeriod            int          `json:"open_period,omitempty"`
+		CloseDate             int64        `json:"close_date,omitempty"`
+	}
+
+	PollAnswer struct {
+		PollID    string `json:"poll_id"`
+		VoterChat *Chat  `json:"voter_chat,omitempty"`
+		User      *User  `json:"user,omitempty"`
+		OptionIDs []int  `json:"option_ids"`
+	}
+
+	// ChatMember agrupa los campos de todas las variantes de miembro de chat.
+	// Status indica la variante: "creator", "administrator", "member",
+	// "restricted", "left" o "kicked".
+	ChatMember struct {
+		Status              string `json:"status"`
+		User                *User  `json:"user"`
+		IsAnonymous         bool   `json:"is_anonymous,omitempty"`
+		CustomTitle         string `json:"custom_title,omitempty"`
+		UntilDate           int64  `json:"until_date,omitempty"`
+		IsMember            bool   `json:"is_member,omitempty"`
+		CanBeEdited         bool   `json:"can_be_edited,omitempty"`
+		CanManageChat       bool   `json:"can_manage_chat,omitempty"`
+		CanDeleteMessages   bool   `json:"can_delete_messages,omitempty"`
+		CanManageVideoChats bool   `json:"can_manage_video_chats,omitempty"`
+		CanRestrictMembers  bool   `json:"can_restrict_members,omitempty"`
+		CanPromoteMembers   bool   `json:"can_promote_members,omitempty"`
+		CanChangeInfo       bool   `json:"can_change_info,omitempty"`
+		CanInviteUsers      bool   `json:"can_invite_users,omitempty"`
+		CanPostMessages     bool   `json:"can_post_messages,omitempty"`
+		CanEditMessages     bool   `json:"can_edit_messages,omitempty"`
+		CanPinMessages      bool   `json:"can_pin_messages,omitempty"`
+		CanManageTopics     bool   `json:"can_manage_topics,omitempty"`
+		CanSendMessages     bool   `json:"can_send_messages,omitempty"`
+	}
+
+	ChatInviteLink struct {
+		InviteLink              string `json:"invite_link"`
+		Creator                 *User  `json:"creator"`
+		CreatesJoinRequest      bool   `json:"creates_join_request"`
+		IsPrimary               bool   `json:"is_primary"`
+		IsRevoked               bool   `json:"is_revoked"`
+		Name                    string `json:"name,omitempty"`
+		ExpireDate              int64  `json:"expire_date,omitempty"`
+		MemberLimit             int    `json:"member_limit,omitempty"`
+		PendingJoinRequestCount int    `json:"pending_join_request_count,omitempty"`
+	}
+
+	ChatMemberUpdated struct {
+		Chat                    *Chat           `json:"chat"`
+		From                    *User           `json:"from"`
+		Date                    int64           `json:"date"`
+		OldChatMember           ChatMember      `json:"old_chat_member"`
+		NewChatMember           ChatMember      `json:"new_chat_member"`
+		InviteLink              *ChatInviteLink `json:"invite_link,omitempty"`
+		ViaJoinRequest          bool            `json:"via_join_request,omitempty"`
+		ViaChatFolderInviteLink bool            `json:"via_chat_folder_invite_link,omitempty"`
+	}
+
+	ChatJoinRequest struct {
+		Chat       *Chat           `json:"chat"`
+		From       *User           `json:"from"`
+		UserChatID int64           `json:"user_chat_id"`
+		Date       int64           `json:"date"`
+		Bio        string          `json:"bio,omitempty"`
+		InviteLink *ChatInviteLink `json:"invite_link,omitempty"`
+	}
+
+	ChatBoostSource struct {
+		Source            string `json:"source"` // "premium", "gift_code", "giveaway"
+		User              *User  `json:"user,omitempty"`
+		GiveawayMessageID int    `json:"giveaway_message_id,omitempty"`
+		IsUnclaimed       bool   `json:"is_unclaimed,omitempty"`
+	}
+
+	ChatBoost struct {
+		BoostID        string          `json:"boost_id"`
+		AddDate        int64           `json:"add_date"`
+		ExpirationDate int64           `json:"expiration_date"`
+		Source         ChatBoostSource `json:"source"`
+	}
+
+	ChatBoostUpdated struct {
+		Chat  *Chat     `json:"chat"`
+		Boost ChatBoost `json:"boost"`
+	}
+
+	ChatBoostRemoved struct {
+		Chat       *Chat           `json:"chat"`
+		BoostID    string          `json:"boost_id"`
+		RemoveDate int64           `json:"remove_date"`
+		Source     ChatBoostSource `json:"source"`
 	}
 
 	Response struct {
-		Ok          bool            `json:"ok"`
-		Result      json.RawMessage `json:"result,omitempty"`
-		Description string          `json:"description,omitempty"`
+		Ok          bool                `json:"ok"`
+		Result      json.RawMessage     `json:"result,omitempty"`
+		Description string              `json:"description,omitempty"`
+		ErrorCode   int                 `json:"error_code,omitempty"`
+		Parameters  *ResponseParameters `json:"parameters,omitempty"`
+	}
+
+	ResponseParameters struct {
+		MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+		RetryAfter      int   `json:"retry_after,omitempty"`
+	}
+
+	// SendOptions contiene los parámetros opcionales comunes a los métodos
+	// de envío. Se configura con opciones SendOption.
+	SendOptions struct {
+		MessageThreadID     int                 `json:"message_thread_id,omitempty"`
+		ParseMode           string              `json:"parse_mode,omitempty"`
+		LinkPreviewOptions  *LinkPreviewOptions `json:"link_preview_options,omitempty"`
+		DisableNotification bool                `json:"disable_notification,omitempty"`
+		ProtectContent      bool                `json:"protect_content,omitempty"`
+		ReplyParameters     *ReplyParameters    `json:"reply_parameters,omitempty"`
+		ReplyMarkup         ReplyMarkup         `json:"reply_markup,omitempty"`
+	}
+
+	ReplyParameters struct {
+		MessageID                int    `json:"message_id"`
+		ChatID                   int64  `json:"chat_id,omitempty"`
+		AllowSendingWithoutReply bool   `json:"allow_sending_without_reply,omitempty"`
+		Quote                    string `json:"quote,omitempty"`
+		QuoteParseMode           string `json:"quote_parse_mode,omitempty"`
+		QuotePosition            int    `json:"quote_position,omitempty"`
+	}
+
+	LinkPreviewOptions struct {
+		IsDisabled       bool   `json:"is_disabled,omitempty"`
+		URL              string `json:"url,omitempty"`
+		PreferSmallMedia bool   `json:"prefer_small_media,omitempty"`
+		PreferLargeMedia bool   `json:"prefer_large_media,omitempty"`
+		ShowAboveText    bool   `json:"show_above_text,omitempty"`
 	}
 
 	SendMessageRequest struct {
 		ChatID int64  `json:"chat_id"`
 		Text   string `json:"text"`
+		SendOptions
+	}
+
+	// ReplyMarkup es implementado por los tipos que pueden enviarse como
+	// reply_markup de un mensaje.
+	ReplyMarkup interface {
+		replyMarkup()
+	}
+
+	InlineKeyboardMarkup struct {
+		InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+	}
+
+	InlineKeyboardButton struct {
+		Text                         string  `json:"text"`
+		URL                          string  `json:"url,omitempty"`
+		CallbackData                 string  `json:"callback_data,omitempty"`
+		SwitchInlineQuery            *string `json:"switch_inline_query,omitempty"`
+		SwitchInlineQueryCurrentChat *string `json:"switch_inline_query_current_chat,omitempty"`
+		Pay                          bool    `json:"pay,omitempty"`
+	}
+
+	// MessageID identifica un mensaje; es el resultado de copyMessage.
+	MessageID struct {
+		MessageID int `json:"message_id"`
+	}
+
+	// EditMessageTextRequest edita un mensaje identificado por ChatID y
+	// MessageID, o por InlineMessageID si fue enviado en modo inline.
+	EditMessageTextRequest struct {
+		ChatID             int64                 `json:"chat_id,omitempty"`
+		MessageID          int                   `json:"message_id,omitempty"`
+		InlineMessageID    string                `json:"inline_message_id,omitempty"`
+		Text               string                `json:"text"`
+		ParseMode          string                `json:"parse_mode,omitempty"`
+		LinkPreviewOptions *LinkPreviewOptions   `json:"link_preview_options,omitempty"`
+		ReplyMarkup        *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	}
+
+	// EditMessageReplyMarkupRequest reemplaza el teclado inline de un mensaje.
+	// Un ReplyMarkup nil elimina el teclado.
+	EditMessageReplyMarkupRequest struct {
+		ChatID          int64                 `json:"chat_id,omitempty"`
+		MessageID       int                   `json:"message_id,omitempty"`
+		InlineMessageID string                `json:"inline_message_id,omitempty"`
+		ReplyMarkup     *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	}
+
+	DeleteMessageRequest struct {
+		ChatID    int64 `json:"chat_id"`
+		MessageID int   `json:"message_id"`
+	}
+
+	DeleteMessagesRequest struct {
+		ChatID     int64 `json:"chat_id"`
+		MessageIDs []int `json:"message_ids"`
+	}
+
+	ForwardMessageRequest struct {
+		ChatID              int64 `json:"chat_id"`
+		MessageThreadID     int   `json:"message_thread_id,omitempty"`
+		FromChatID          int64 `json:"from_chat_id"`
+		MessageID           int   `json:"message_id"`
+		DisableNotification bool  `json:"disable_notification,omitempty"`
+		ProtectContent      bool  `json:"protect_content,omitempty"`
+	}
+
+	// CopyMessageRequest copia un mensaje sin el enlace al mensaje original.
+	// Caption reemplaza el texto del mensaje copiado si no está vacío.
+	CopyMessageRequest struct {
+		ChatID     int64  `json:"chat_id"`
+		FromChatID int64  `json:"from_chat_id"`
+		MessageID  int    `json:"message_id"`
+		Caption    string `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	// Requests de los métodos de envío de archivos. El archivo se envía por
+	// file_id, URL o se sube como multipart según cómo se construyó el InputFile.
+	SendPhotoRequest struct {
+		ChatID  int64     `json:"chat_id"`
+		Photo   InputFile `json:"photo"`
+		Caption string    `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	SendDocumentRequest struct {
+		ChatID   int64     `json:"chat_id"`
+		Document InputFile `json:"document"`
+		Caption  string    `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	SendAudioRequest struct {
+		ChatID  int64     `json:"chat_id"`
+		Audio   InputFile `json:"audio"`
+		Caption string    `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	SendVideoRequest struct {
+		ChatID  int64     `json:"chat_id"`
+		Video   InputFile `json:"video"`
+		Caption string    `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	SendVoiceRequest struct {
+		ChatID  int64     `json:"chat_id"`
+		Voice   InputFile `json:"voice"`
+		Caption string    `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	SendAnimationRequest struct {
+		ChatID    int64     `json:"chat_id"`
+		Animation InputFile `json:"animation"`
+		Caption   string    `json:"caption,omitempty"`
+		SendOptions
+	}
+
+	// BotCommand es un comando tal como aparece en el menú del cliente.
+	BotCommand struct {
+		Command     string `json:"command"`
+		Description string `json:"description"`
+	}
+
+	// BotCommandScope indica a qué chats y usuarios se muestra un menú de
+	// comandos. Se construye con ScopeDefault, ScopeAllPrivateChats, etc.
+	BotCommandScope struct {
+		Type   string `json:"type"`
+		ChatID int64  `json:"chat_id,omitempty"`
+		UserID int64  `json:"user_id,omitempty"`
+	}
+
+	SetMyCommandsRequest struct {
+		Commands     []BotCommand     `json:"commands"`
+		Scope        *BotCommandScope `json:"scope,omitempty"`
+		LanguageCode string           `json:"language_code,omitempty"`
+	}
+
+	// MyCommandsRequest identifica un menú de comandos en getMyCommands y
+	// deleteMyCommands.
+	MyCommandsRequest struct {
+		Scope        *BotCommandScope `json:"scope,omitempty"`
+		LanguageCode string           `json:"language_code,omitempty"`
+	}
+
+	AnswerCallbackQueryRequest struct {
+		CallbackQueryID string `json:"callback_query_id"`
+		Text            string `json:"text,omitempty"`
+		ShowAlert       bool   `json:"show_alert,omitempty"`
+		URL             string `json:"url,omitempty"`
+		CacheTime       int    `json:"cache_time,omitempty"`
+	}
+
+	SetWebhookRequest struct {
+		URL                string   `json:"url"`
+		IPAddress          string   `json:"ip_address,omitempty"`
+		MaxConnections     int      `json:"max_connections,omitempty"`
+		AllowedUpdates     []string `json:"allowed_updates,omitempty"`
+		DropPendingUpdates bool     `json:"drop_pending_updates,omitempty"`
+		SecretToken        string   `json:"secret_token,omitempty"`
+	}
+
+	DeleteWebhookRequest struct {
+		DropPendingUpdates bool `json:"drop_pending_updates,omitempty"`
+	}
+
+	WebhookInfo struct {
+		URL                          string   `json:"url"`
+		HasCustomCertificate         bool     `json:"has_custom_certificate"`
+		PendingUpdateCount           int      `json:"pending_update_count"`
+		IPAddress                    string   `json:"ip_address,omitempty"`
+		LastErrorDate                int64    `json:"last_error_date,omitempty"`
+		LastErrorMessage             string   `json:"last_error_message,omitempty"`
+		LastSynchronizationErrorDate int64    `json:"last_synchronization_error_date,omitempty"`
+		MaxConnections               int      `json:"max_connections,omitempty"`
+		AllowedUpdates               []string `json:"allowed_updates,omitempty"`
 	}
 )
@@ -0,0 +1,300 @@
+package bot
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMessage_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		check func(t *testing.T, msg *Message)
+	}{
+		{
+			name: "text with entities and reply",
+			raw: `{
+				"message_id": 1042,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana", "username": "ana", "language_code": "es"},
+				"chat": {"id": -1001234567890, "type": "supergroup", "title": "Equipo"},
+				"date": 1717000000,
+				"reply_to_message": {
+					"message_id": 1040,
+					"from": {"id": 222, "is_bot": false, "first_name": "Luis"},
+					"chat": {"id": -1001234567890, "type": "supergroup", "title": "Equipo"},
+					"date": 1716999000,
+					"text": "¿Quién revisa el PR?"
+				},
+				"text": "/assign@equipo_bot @luis https://example.com",
+				"entities": [
+					{"type": "bot_command", "offset": 0, "length": 19},
+					{"type": "mention", "offset": 20, "length": 5},
+					{"type": "url", "offset": 26, "length": 19}
+				]
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if len(msg.Entities) != 3 || msg.Entities[0].Type != "bot_command" {
+					t.Errorf("unexpected entities %+v", msg.Entities)
+				}
+				if msg.ReplyToMessage == nil || msg.ReplyToMessage.MessageID != 1040 {
+					t.Errorf("expected reply to message 1040, got %+v", msg.ReplyToMessage)
+				}
+			},
+		},
+		{
+			name: "photo album item with caption",
+			raw: `{
+				"message_id": 2001,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": 111, "type": "private", "first_name": "Ana"},
+				"date": 1717000100,
+				"media_group_id": "13713971301234567",
+				"photo": [
+					{"file_id": "AgACAgEAAxkBAAIH0WZ-small", "file_unique_id": "AQADsmall", "width": 90, "height": 67, "file_size": 1289},
+					{"file_id": "AgACAgEAAxkBAAIH0WZ-medium", "file_unique_id": "AQADmedium", "width": 320, "height": 240, "file_size": 17865},
+					{"file_id": "AgACAgEAAxkBAAIH0WZ-large", "file_unique_id": "AQADlarge", "width": 1280, "height": 960, "file_size": 140231}
+				],
+				"caption": "Pizarra de la reunión",
+				"caption_entities": [{"type": "bold", "offset": 0, "length": 8}]
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if len(msg.Photo) != 3 || msg.Photo[2].Width != 1280 {
+					t.Errorf("unexpected photo sizes %+v", msg.Photo)
+				}
+				if msg.Text != "" || msg.Caption != "Pizarra de la reunión" {
+					t.Errorf("expected caption without text, got text=%q caption=%q", msg.Text, msg.Caption)
+				}
+				if msg.MediaGroupID == "" || len(msg.CaptionEntities) != 1 {
+					t.Errorf("expected media group and caption entities, got %+v", msg)
+				}
+			},
+		},
+		{
+			name: "document",
+			raw: `{
+				"message_id": 2002,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": 111, "type": "private", "first_name": "Ana"},
+				"date": 1717000200,
+				"document": {
+					"file_id": "BQACAgEAAxkBAAIH0mZ",
+					"file_unique_id": "AgADdoc",
+					"file_name": "ventas.csv",
+					"mime_type": "text/csv",
+					"file_size": 48213
+				}
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.Document == nil || msg.Document.FileName != "ventas.csv" {
+					t.Errorf("unexpected document %+v", msg.Document)
+				}
+			},
+		},
+		{
+			name: "forwarded from channel",
+			raw: `{
+				"message_id": 2003,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": 111, "type": "private", "first_name": "Ana"},
+				"date": 1717000300,
+				"forward_origin": {
+					"type": "channel",
+					"date": 1716900000,
+					"chat": {"id": -1009876543210, "type": "channel", "title": "Novedades", "username": "novedades"},
+					"message_id": 77,
+					"author_signature": "Editor"
+				},
+				"text": "Nueva versión publicada"
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.ForwardOrigin == nil || msg.ForwardOrigin.Type != "channel" || msg.ForwardOrigin.Chat.Title != "Novedades" {
+					t.Errorf("unexpected forward origin %+v", msg.ForwardOrigin)
+				}
+			},
+		},
+		{
+			name: "forum topic message edited by channel",
+			raw: `{
+				"message_id": 3001,
+				"message_thread_id": 42,
+				"sender_chat": {"id": -1009876543210, "type": "channel", "title": "Novedades"},
+				"chat": {"id": -1001234567890, "type": "supergroup", "title": "Comunidad", "is_forum": true},
+				"date": 1717000400,
+				"edit_date": 1717000450,
+				"is_topic_message": true,
+				"text": "Anuncio corregido"
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.MessageThreadID != 42 || msg.EditDate == 0 || msg.SenderChat == nil {
+					t.Errorf("unexpected topic message %+v", msg)
+				}
+			},
+		},
+		{
+			name: "media kinds",
+			raw: `{
+				"message_id": 4001,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": 111, "type": "private", "first_name": "Ana"},
+				"date": 1717000500,
+				"audio": {"file_id": "CQACAgEAAx0", "file_unique_id": "AgADaudio", "duration": 215, "performer": "Banda", "title": "Canción", "mime_type": "audio/mpeg", "file_size": 3456789},
+				"video": {"file_id": "BAACAgEAAx0", "file_unique_id": "AgADvideo", "width": 1920, "height": 1080, "duration": 12, "thumbnail": {"file_id": "AAMCAQ", "file_unique_id": "AQADthumb", "width": 320, "height": 180}, "mime_type": "video/mp4", "file_size": 2345678},
+				"voice": {"file_id": "AwACAgEAAx0", "file_unique_id": "AgADvoice", "duration": 4, "mime_type": "audio/ogg", "file_size": 14321},
+				"animation": {"file_id": "CgACAgEAAx0", "file_unique_id": "AgADanim", "width": 480, "height": 270, "duration": 3, "file_name": "gato.mp4", "mime_type": "video/mp4"},
+				"sticker": {"file_id": "CAACAgIAAx0", "file_unique_id": "AgADsticker", "type": "regular", "width": 512, "height": 512, "is_animated": false, "is_video": false, "emoji": "👍", "set_name": "Animals"}
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.Audio == nil || msg.Video == nil || msg.Voice == nil || msg.Animation == nil || msg.Sticker == nil {
+					t.Errorf("expected all media to be decoded, got %+v", msg)
+				}
+				if msg.Video.Thumbnail == nil || msg.Sticker.Emoji != "👍" {
+					t.Errorf("unexpected media details %+v %+v", msg.Video, msg.Sticker)
+				}
+			},
+		},
+		{
+			name: "contact and location",
+			raw: `{
+				"message_id": 4002,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": 111, "type": "private", "first_name": "Ana"},
+				"date": 1717000600,
+				"contact": {"phone_number": "+5491122334455", "first_name": "Ana", "last_name": "García", "user_id": 111},
+				"location": {"latitude": -34.603722, "longitude": -58.381592, "horizontal_accuracy": 12.5}
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.Contact == nil || msg.Contact.UserID != 111 {
+					t.Errorf("unexpected contact %+v", msg.Contact)
+				}
+				if msg.Location == nil || msg.Location.Latitude != -34.603722 {
+					t.Errorf("unexpected location %+v", msg.Location)
+				}
+			},
+		},
+		{
+			name: "new chat members",
+			raw: `{
+				"message_id": 5001,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": -1001234567890, "type": "supergroup", "title": "Equipo"},
+				"date": 1717000700,
+				"new_chat_members": [
+					{"id": 333, "is_bot": false, "first_name": "Marta"},
+					{"id": 999, "is_bot": true, "first_name": "Equipo Bot", "username": "equipo_bot"}
+				]
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if len(msg.NewChatMembers) != 2 || !msg.NewChatMembers[1].IsBot {
+					t.Errorf("unexpected new members %+v", msg.NewChatMembers)
+				}
+			},
+		},
+		{
+			name: "left chat member",
+			raw: `{
+				"message_id": 5002,
+				"from": {"id": 333, "is_bot": false, "first_name": "Marta"},
+				"chat": {"id": -1001234567890, "type": "supergroup", "title": "Equipo"},
+				"date": 1717000800,
+				"left_chat_member": {"id": 333, "is_bot": false, "first_name": "Marta"}
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.LeftChatMember == nil || msg.LeftChatMember.ID != 333 {
+					t.Errorf("unexpected left member %+v", msg.LeftChatMember)
+				}
+			},
+		},
+		{
+			name: "pinned message",
+			raw: `{
+				"message_id": 5003,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": -1001234567890, "type": "supergroup", "title": "Equipo"},
+				"date": 1717000900,
+				"pinned_message": {
+					"message_id": 1042,
+					"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+					"chat": {"id": -1001234567890, "type": "supergroup", "title": "Equipo"},
+					"date": 1717000000,
+					"text": "Reglas del grupo"
+				}
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.PinnedMessage == nil || msg.PinnedMessage.Text != "Reglas del grupo" {
+					t.Errorf("unexpected pinned message %+v", msg.PinnedMessage)
+				}
+			},
+		},
+		{
+			name: "group migrated to supergroup",
+			raw: `{
+				"message_id": 5004,
+				"from": {"id": 111, "is_bot": false, "first_name": "Ana"},
+				"chat": {"id": -4012345678, "type": "group", "title": "Equipo"},
+				"date": 1717001000,
+				"migrate_to_chat_id": -1001234567890
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.MigrateToChatID != -1001234567890 {
+					t.Errorf("expected migrate_to_chat_id, got %d", msg.MigrateToChatID)
+				}
+			},
+		},
+		{
+			name: "message with inline keyboard",
+			raw: `{
+				"message_id": 6001,
+				"from": {"id": 999, "is_bot": true, "first_name": "Equipo Bot", "username": "equipo_bot"},
+				"chat": {"id": 111, "type": "private", "first_name": "Ana"},
+				"date": 1717001100,
+				"text": "¿Confirmar?",
+				"reply_markup": {"inline_keyboard": [[{"text": "Sí", "callback_data": "confirm:yes"}, {"text": "No", "callback_data": "confirm:no"}]]}
+			}`,
+			check: func(t *testing.T, msg *Message) {
+				if msg.ReplyMarkup == nil || len(msg.ReplyMarkup.InlineKeyboard[0]) != 2 {
+					t.Errorf("unexpected reply markup %+v", msg.ReplyMarkup)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg Message
+			if err := json.Unmarshal([]byte(tt.raw), &msg); err != nil {
+				t.Fatalf("error unmarshaling message: %v", err)
+			}
+
+			tt.check(t, &msg)
+
+			encoded, err := json.Marshal(&msg)
+			if err != nil {
+				t.Fatalf("error marshaling message: %v", err)
+			}
+
+			var want, got any
+			if err := json.Unmarshal([]byte(tt.raw), &want); err != nil {
+				t.Fatalf("invalid fixture: %v", err)
+			}
+			if err := json.Unmarshal(encoded, &got); err != nil {
+				t.Fatalf("error decoding encoded message: %v", err)
+			}
+
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("round trip mismatch\nwant: %s\ngot:  %s", compactJSON(t, tt.raw), encoded)
+			}
+		})
+	}
+}
+
+func compactJSON(t *testing.T, raw string) string {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
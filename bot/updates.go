@@ -0,0 +1,488 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+)
+
+// Tipos de actualización de la API de Telegram. Se usan con
+// WithAllowedUpdates y son los valores que retorna Update.Type.
+const (
+	UpdateTypeMessage                 = "message"
+	UpdateTypeEditedMessage           = "edited_message"
+	UpdateTypeChannelPost             = "channel_post"
+	UpdateTypeEditedChannelPost       = "edited_channel_post"
+	UpdateTypeBusinessConnection      = "business_connection"
+	UpdateTypeBusinessMessage         = "business_message"
+	UpdateTypeEditedBusinessMessage   = "edited_business_message"
+	UpdateTypeDeletedBusinessMessages = "deleted_business_messages"
+	UpdateTypeMessageReaction         = "message_reaction"
+	UpdateTypeMessageReactionCount    = "message_reaction_count"
+	UpdateTypeInlineQuery             = "inline_query"
+	UpdateTypeChosenInlineResult      = "chosen_inline_result"
+	UpdateTypeCallbackQuery           = "callback_query"
+	UpdateTypeShippingQuery           = "shipping_query"
+	UpdateTypePreCheckoutQuery        = "pre_checkout_query"
+	UpdateTypePurchasedPaidMedia      = "purchased_paid_media"
+	UpdateTypePoll                    = "poll"
+	UpdateTypePollAnswer              = "poll_answer"
+	UpdateTypeMyChatMember            = "my_chat_member"
+	UpdateTypeChatMember              = "chat_member"
+	UpdateTypeChatJoinRequest         = "chat_join_request"
+	UpdateTypeChatBoost               = "chat_boost"
+	UpdateTypeRemovedChatBoost        = "removed_chat_boost"
+)
+
+// Type retorna el tipo de la actualización, o una cadena vacía si el update
+// no contiene ningún tipo conocido.
+func (u *Update) Type() string {
+	switch {
+	case u.Message != nil:
+		return UpdateTypeMessage
+	case u.EditedMessage != nil:
+		return UpdateTypeEditedMessage
+	case u.ChannelPost != nil:
+		return UpdateTypeChannelPost
+	case u.EditedChannelPost != nil:
+		return UpdateTypeEditedChannelPost
+	case u.BusinessConnection != nil:
+		return UpdateTypeBusinessConnection
+	case u.BusinessMessage != nil:
+		return UpdateTypeBusinessMessage
+	case u.EditedBusinessMessage != nil:
+		return UpdateTypeEditedBusinessMessage
+	case u.DeletedBusinessMessages != nil:
+		return UpdateTypeDeletedBusinessMessages
+	case u.MessageReaction != nil:
+		return UpdateTypeMessageReaction
+	case u.MessageReactionCount != nil:
+		return UpdateTypeMessageReactionCount
+	case u.InlineQuery != nil:
+		return UpdateTypeInlineQuery
+	case u.ChosenInlineResult != nil:
+		return UpdateTypeChosenInlineResult
+	case u.CallbackQuery != nil:
+		return UpdateTypeCallbackQuery
+	case u.ShippingQuery != nil:
+		return UpdateTypeShippingQuery
+	case u.PreCheckoutQuery != nil:
+		return UpdateTypePreCheckoutQuery
+	case u.PurchasedPaidMedia != nil:
+		return UpdateTypePurchasedPaidMedia
+	case u.Poll != nil:
+		return UpdateTypePoll
+	case u.PollAnswer != nil:
+		return UpdateTypePollAnswer
+	case u.MyChatMember != nil:
+		return UpdateTypeMyChatMember
+	case u.ChatMember != nil:
+		return UpdateTypeChatMember
+	case u.ChatJoinRequest != nil:
+		return UpdateTypeChatJoinRequest
+	case u.ChatBoost != nil:
+		return UpdateTypeChatBoost
+	case u.RemovedChatBoost != nil:
+		return UpdateTypeRemovedChatBoost
+	}
+	return ""
+}
+
+// Chat retorna el chat al que pertenece la actualización, o nil si no
+// pertenece a un chat, como las inline queries o los pagos.
+func (u *Update) Chat() *Chat {
+	if msg := u.message(); msg != nil {
+		return msg.Chat
+	}
+
+	switch {
+	case u.DeletedBusinessMessages != nil:
+		return u.DeletedBusinessMessages.Chat
+	case u.MessageReaction != nil:
+		return u.MessageReaction.Chat
+	case u.MessageReactionCount != nil:
+		return u.MessageReactionCount.Chat
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		return u.CallbackQuery.Message.Chat
+	case u.MyChatMember != nil:
+		return u.MyChatMember.Chat
+	case u.ChatMember != nil:
+		return u.ChatMember.Chat
+	case u.ChatJoinRequest != nil:
+		return u.ChatJoinRequest.Chat
+	case u.ChatBoost != nil:
+		return u.ChatBoost.Chat
+	case u.RemovedChatBoost != nil:
+		return u.RemovedChatBoost.Chat
+	}
+	return nil
+}
+
+// From retorna el usuario que originó la actualización, o nil si no hay uno,
+// como en las encuestas o los mensajes de canales.
+func (u *Update) From() *User {
+	if msg := u.message(); msg != nil {
+		return msg.From
+	}
+
+	switch {
+	case u.BusinessConnection != nil:
+		return u.BusinessConnection.User
+	case u.MessageReaction != nil:
+		return u.MessageReaction.User
+	case u.InlineQuery != nil:
+		return u.InlineQuery.From
+	case u.ChosenInlineResult != nil:
+		return u.ChosenInlineResult.From
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.ShippingQuery != nil:
+		return u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return u.PreCheckoutQuery.From
+	case u.PurchasedPaidMedia != nil:
+		return u.PurchasedPaidMedia.From
+	case u.PollAnswer != nil:
+		return u.PollAnswer.User
+	case u.MyChatMember != nil:
+		return u.MyChatMember.From
+	case u.ChatMember != nil:
+		return u.ChatMember.From
+	case u.ChatJoinRequest != nil:
+		return u.ChatJoinRequest.From
+	}
+	return nil
+}
+
+// decodeUpdate decodifica una actualización conservando el JSON recibido,
+// que incluye los campos que Update no modela.
+func decodeUpdate(data json.RawMessage) (*Update, error) {
+	var update Update
+	if err := json.Unmarshal(data, &update); err != nil {
+		return nil, err
+	}
+	update.raw = data
+	return &update, nil
+}
+
+// rawJSON retorna el JSON recibido de Telegram o, si la actualización no se
+// obtuvo con decodeUpdate, el de sus campos.
+func (u *Update) rawJSON() json.RawMessage {
+	if u.raw != nil {
+		return u.raw
+	}
+	data, _ := json.Marshal(u)
+	return data
+}
+
+// message retorna el mensaje de la actualización, si es de alguno de los
+// tipos que contienen un Message.
+func (u *Update) message() *Message {
+	switch {
+	case u.Message != nil:
+		return u.Message
+	case u.EditedMessage != nil:
+		return u.EditedMessage
+	case u.ChannelPost != nil:
+		return u.ChannelPost
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost
+	case u.BusinessMessage != nil:
+		return u.BusinessMessage
+	case u.EditedBusinessMessage != nil:
+		return u.EditedBusinessMessage
+	}
+	return nil
+}
+
+// updateHandlers contiene los handlers registrados para cada tipo de
+// actualización. Los mensajes se procesan con handleMessage, que usa message
+// y unknownCommand cuando no hay un comando registrado que los atienda.
+type updateHandlers struct {
+	message                 func(context.Context, *Bot, *Message) error
+	unknownCommand          func(context.Context, *Bot, *Message) error
+	update                  func(context.Context, *Bot, *Update) error
+	editedMessage           func(context.Context, *Bot, *Message) error
+	channelPost             func(context.Context, *Bot, *Message) error
+	editedChannelPost       func(context.Context, *Bot, *Message) error
+	businessConnection      func(context.Context, *Bot, *BusinessConnection) error
+	businessMessage         func(context.Context, *Bot, *Message) error
+	editedBusinessMessage   func(context.Context, *Bot, *Message) error
+	deletedBusinessMessages func(context.Context, *Bot, *BusinessMessagesDeleted) error
+	messageReaction         func(context.Context, *Bot, *MessageReactionUpdated) error
+	messageReactionCount    func(context.Context, *Bot, *MessageReactionCountUpdated) error
+	inlineQuery             func(context.Context, *Bot, *InlineQuery) error
+	chosenInlineResult      func(context.Context, *Bot, *ChosenInlineResult) error
+	callbackQuery           func(context.Context, *Bot, *CallbackQuery) error
+	shippingQuery           func(context.Context, *Bot, *ShippingQuery) error
+	preCheckoutQuery        func(context.Context, *Bot, *PreCheckoutQuery) error
+	purchasedPaidMedia      func(context.Context, *Bot, *PaidMediaPurchased) error
+	poll                    func(context.Context, *Bot, *Poll) error
+	pollAnswer              func(context.Context, *Bot, *PollAnswer) error
+	myChatMember            func(context.Context, *Bot, *ChatMemberUpdated) error
+	chatMember              func(context.Context, *Bot, *ChatMemberUpdated) error
+	chatJoinRequest         func(context.Context, *Bot, *ChatJoinRequest) error
+	chatBoost               func(context.Context, *Bot, *ChatBoostUpdated) error
+	removedChatBoost        func(context.Context, *Bot, *ChatBoostRemoved) error
+}
+
+// WithAllowedUpdates configura los tipos de actualización que Telegram debe
+// enviar en getUpdates. Telegram no envía chat_member, message_reaction ni
+// message_reaction_count a menos que se pidan explícitamente.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithAllowedUpdates(
+//	    bot.UpdateTypeMessage,
+//	    bot.UpdateTypeCallbackQuery,
+//	    bot.UpdateTypeChatMember,
+//	))
+func WithAllowedUpdates(types ...string) BotOption {
+	return func(b *Bot) {
+		b.allowedUpdates = types
+	}
+}
+
+// OnMessage registra el handler para los mensajes que no son comandos: texto,
+// fotos, documentos, mensajes de servicio, etc. Sin este handler esos mensajes
+// se descartan.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.OnMessage(func(ctx context.Context, b *bot.Bot, msg *bot.Message) error {
+//	    if msg.Chat.Type == "private" && msg.Text != "" {
+//	        _, err := b.SendMessage(ctx, msg.Chat.ID, "Usa /help para ver los comandos disponibles.")
+//	        return err
+//	    }
+//	    return nil
+//	}))
+func OnMessage(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.message = handler }
+}
+
+// OnUnknownCommand registra el handler para los comandos dirigidos al bot que
+// no están en el CommandRegistry. Los comandos dirigidos a otros bots
+// (/start@OtroBot) se ignoran.
+func OnUnknownCommand(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.unknownCommand = handler }
+}
+
+// OnUpdate registra un handler para todas las actualizaciones que no atendió
+// ningún otro handler, incluidos los mensajes sin handler y los tipos de
+// actualización sin una opción On* registrada.
+func OnUpdate(handler func(context.Context, *Bot, *Update) error) BotOption {
+	return func(b *Bot) { b.handlers.update = handler }
+}
+
+// OnEditedMessage registra el handler para mensajes editados.
+func OnEditedMessage(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.editedMessage = handler }
+}
+
+// OnChannelPost registra el handler para publicaciones en canales.
+func OnChannelPost(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.channelPost = handler }
+}
+
+// OnEditedChannelPost registra el handler para publicaciones editadas en canales.
+func OnEditedChannelPost(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.editedChannelPost = handler }
+}
+
+// OnBusinessConnection registra el handler para conexiones con cuentas business.
+func OnBusinessConnection(handler func(context.Context, *Bot, *BusinessConnection) error) BotOption {
+	return func(b *Bot) { b.handlers.businessConnection = handler }
+}
+
+// OnBusinessMessage registra el handler para mensajes de cuentas business conectadas.
+func OnBusinessMessage(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.businessMessage = handler }
+}
+
+// OnEditedBusinessMessage registra el handler para mensajes business editados.
+func OnEditedBusinessMessage(handler func(context.Context, *Bot, *Message) error) BotOption {
+	return func(b *Bot) { b.handlers.editedBusinessMessage = handler }
+}
+
+// OnDeletedBusinessMessages registra el handler para mensajes business eliminados.
+func OnDeletedBusinessMessages(handler func(context.Context, *Bot, *BusinessMessagesDeleted) error) BotOption {
+	return func(b *Bot) { b.handlers.deletedBusinessMessages = handler }
+}
+
+// OnMessageReaction registra el handler para cambios de reacciones de un usuario.
+// Requiere incluir UpdateTypeMessageReaction en WithAllowedUpdates.
+func OnMessageReaction(handler func(context.Context, *Bot, *MessageReactionUpdated) error) BotOption {
+	return func(b *Bot) { b.handlers.messageReaction = handler }
+}
+
+// OnMessageReactionCount registra el handler para cambios de reacciones anónimas.
+// Requiere incluir UpdateTypeMessageReactionCount en WithAllowedUpdates.
+func OnMessageReactionCount(handler func(context.Context, *Bot, *MessageReactionCountUpdated) error) BotOption {
+	return func(b *Bot) { b.handlers.messageReactionCount = handler }
+}
+
+// OnInlineQuery registra el handler para consultas inline.
+func OnInlineQuery(handler func(context.Context, *Bot, *InlineQuery) error) BotOption {
+	return func(b *Bot) { b.handlers.inlineQuery = handler }
+}
+
+// OnChosenInlineResult registra el handler para resultados inline elegidos.
+func OnChosenInlineResult(handler func(context.Context, *Bot, *ChosenInlineResult) error) BotOption {
+	return func(b *Bot) { b.handlers.chosenInlineResult = handler }
+}
+
+// OnCallbackQuery registra el handler para callback queries de teclados inline.
+func OnCallbackQuery(handler func(context.Context, *Bot, *CallbackQuery) error) BotOption {
+	return func(b *Bot) { b.handlers.callbackQuery = handler }
+}
+
+// OnShippingQuery registra el handler para consultas de envío de pagos.
+func OnShippingQuery(handler func(context.Context, *Bot, *ShippingQuery) error) BotOption {
+	return func(b *Bot) { b.handlers.shippingQuery = handler }
+}
+
+// OnPreCheckoutQuery registra el handler para confirmaciones previas al pago.
+func OnPreCheckoutQuery(handler func(context.Context, *Bot, *PreCheckoutQuery) error) BotOption {
+	return func(b *Bot) { b.handlers.preCheckoutQuery = handler }
+}
+
+// OnPurchasedPaidMedia registra el handler para compras de contenido pago.
+func OnPurchasedPaidMedia(handler func(context.Context, *Bot, *PaidMediaPurchased) error) BotOption {
+	return func(b *Bot) { b.handlers.purchasedPaidMedia = handler }
+}
+
+// OnPoll registra el handler para cambios de estado de encuestas.
+func OnPoll(handler func(context.Context, *Bot, *Poll) error) BotOption {
+	return func(b *Bot) { b.handlers.poll = handler }
+}
+
+// OnPollAnswer registra el handler para votos en encuestas no anónimas.
+func OnPollAnswer(handler func(context.Context, *Bot, *PollAnswer) error) BotOption {
+	return func(b *Bot) { b.handlers.pollAnswer = handler }
+}
+
+// OnMyChatMember registra el handler para cambios del estado del bot en un chat.
+func OnMyChatMember(handler func(context.Context, *Bot, *ChatMemberUpdated) error) BotOption {
+	return func(b *Bot) { b.handlers.myChatMember = handler }
+}
+
+// OnChatMember registra el handler para cambios de estado de miembros de un chat.
+// Requiere que el bot sea administrador y incluir UpdateTypeChatMember en
+// WithAllowedUpdates.
+func OnChatMember(handler func(context.Context, *Bot, *ChatMemberUpdated) error) BotOption {
+	return func(b *Bot) { b.handlers.chatMember = handler }
+}
+
+// OnChatJoinRequest registra el handler para solicitudes de ingreso a un chat.
+func OnChatJoinRequest(handler func(context.Context, *Bot, *ChatJoinRequest) error) BotOption {
+	return func(b *Bot) { b.handlers.chatJoinRequest = handler }
+}
+
+// OnChatBoost registra el handler para boosts agregados o modificados.
+func OnChatBoost(handler func(context.Context, *Bot, *ChatBoostUpdated) error) BotOption {
+	return func(b *Bot) { b.handlers.chatBoost = handler }
+}
+
+// OnRemovedChatBoost registra el handler para boosts eliminados.
+func OnRemovedChatBoost(handler func(context.Context, *Bot, *ChatBoostRemoved) error) BotOption {
+	return func(b *Bot) { b.handlers.removedChatBoost = handler }
+}
+
+// handleUpdate procesa una actualización recibida por long polling o webhook
+// y registra el error, si lo hubo.
+func (b *Bot) handleUpdate(ctx context.Context, update *Update) {
+	if err := b.runUpdate(ctx, update); err != nil {
+		b.logger.Error("Error procesando update",
+			slog.Int("update_id", update.UpdateID),
+			slog.String("type", update.Type()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// runUpdate procesa una actualización pasándola por los middlewares
+// configurados con WithMiddleware.
+func (b *Bot) runUpdate(ctx context.Context, update *Update) error {
+	ctx = context.WithValue(ctx, updateContextKey{}, update)
+	return chain(routeUpdate, b.middlewares)(ctx, b, update)
+}
+
+// routeUpdate despacha la actualización al handler registrado para su tipo.
+func routeUpdate(ctx context.Context, b *Bot, update *Update) error {
+	h := &b.handlers
+
+	var (
+		handled bool
+		err     error
+	)
+	switch {
+	case update.Message != nil:
+		handled, err = b.handleMessage(ctx, update.Message)
+	case update.EditedMessage != nil:
+		handled, err = dispatch(ctx, b, h.editedMessage, update.EditedMessage)
+	case update.ChannelPost != nil:
+		handled, err = dispatch(ctx, b, h.channelPost, update.ChannelPost)
+	case update.EditedChannelPost != nil:
+		handled, err = dispatch(ctx, b, h.editedChannelPost, update.EditedChannelPost)
+	case update.BusinessConnection != nil:
+		handled, err = dispatch(ctx, b, h.businessConnection, update.BusinessConnection)
+	case update.BusinessMessage != nil:
+		handled, err = dispatch(ctx, b, h.businessMessage, update.BusinessMessage)
+	case update.EditedBusinessMessage != nil:
+		handled, err = dispatch(ctx, b, h.editedBusinessMessage, update.EditedBusinessMessage)
+	case update.DeletedBusinessMessages != nil:
+		handled, err = dispatch(ctx, b, h.deletedBusinessMessages, update.DeletedBusinessMessages)
+	case update.MessageReaction != nil:
+		handled, err = dispatch(ctx, b, h.messageReaction, update.MessageReaction)
+	case update.MessageReactionCount != nil:
+		handled, err = dispatch(ctx, b, h.messageReactionCount, update.MessageReactionCount)
+	case update.InlineQuery != nil:
+		handled, err = dispatch(ctx, b, h.inlineQuery, update.InlineQuery)
+	case update.ChosenInlineResult != nil:
+		handled, err = dispatch(ctx, b, h.chosenInlineResult, update.ChosenInlineResult)
+	case update.CallbackQuery != nil:
+		handled, err = b.handleCallbackQuery(ctx, update.CallbackQuery)
+	case update.ShippingQuery != nil:
+		handled, err = dispatch(ctx, b, h.shippingQuery, update.ShippingQuery)
+	case update.PreCheckoutQuery != nil:
+		handled, err = dispatch(ctx, b, h.preCheckoutQuery, update.PreCheckoutQuery)
+	case update.PurchasedPaidMedia != nil:
+		handled, err = dispatch(ctx, b, h.purchasedPaidMedia, update.PurchasedPaidMedia)
+	case update.Poll != nil:
+		handled, err = dispatch(ctx, b, h.poll, update.Poll)
+	case update.PollAnswer != nil:
+		handled, err = dispatch(ctx, b, h.pollAnswer, update.PollAnswer)
+	case update.MyChatMember != nil:
+		handled, err = dispatch(ctx, b, h.myChatMember, update.MyChatMember)
+	case update.ChatMember != nil:
+		handled, err = dispatch(ctx, b, h.chatMember, update.ChatMember)
+	case update.ChatJoinRequest != nil:
+		handled, err = dispatch(ctx, b, h.chatJoinRequest, update.ChatJoinRequest)
+	case update.ChatBoost != nil:
+		handled, err = dispatch(ctx, b, h.chatBoost, update.ChatBoost)
+	case update.RemovedChatBoost != nil:
+		handled, err = dispatch(ctx, b, h.removedChatBoost, update.RemovedChatBoost)
+	}
+
+	if !handled {
+		handled, err = dispatch(ctx, b, h.update, update)
+	}
+
+	if !handled {
+		b.logger.Debug("Update sin handler registrado",
+			slog.Int("update_id", update.UpdateID),
+			slog.String("type", update.Type()),
+		)
+	}
+
+	return err
+}
+
+// dispatch invoca el handler si está registrado e indica si lo hizo, junto
+// con el error del handler.
+func dispatch[T any](ctx context.Context, b *Bot, handler func(context.Context, *Bot, *T) error, value *T) (bool, error) {
+	if handler == nil {
+		return false, nil
+	}
+	return true, handler(ctx, b, value)
+}
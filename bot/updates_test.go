@@ -0,0 +1,319 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBot_handleUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		wantType string
+		option   func(called *string) BotOption
+	}{
+		{
+			name:     "edited message",
+			raw:      `{"update_id":1,"edited_message":{"message_id":1,"chat":{"id":1,"type":"private"},"date":1,"edit_date":2,"text":"edited"}}`,
+			wantType: UpdateTypeEditedMessage,
+			option: func(called *string) BotOption {
+				return OnEditedMessage(func(ctx context.Context, b *Bot, msg *Message) error { *called = msg.Text; return nil })
+			},
+		},
+		{
+			name:     "channel post",
+			raw:      `{"update_id":1,"channel_post":{"message_id":1,"chat":{"id":-100,"type":"channel"},"date":1,"text":"post"}}`,
+			wantType: UpdateTypeChannelPost,
+			option: func(called *string) BotOption {
+				return OnChannelPost(func(ctx context.Context, b *Bot, msg *Message) error { *called = msg.Text; return nil })
+			},
+		},
+		{
+			name:     "callback query",
+			raw:      `{"update_id":1,"callback_query":{"id":"42","from":{"id":1,"first_name":"Test"},"chat_instance":"ci","data":"button:1"}}`,
+			wantType: UpdateTypeCallbackQuery,
+			option: func(called *string) BotOption {
+				return OnCallbackQuery(func(ctx context.Context, b *Bot, q *CallbackQuery) error { *called = q.Data; return nil })
+			},
+		},
+		{
+			name:     "inline query",
+			raw:      `{"update_id":1,"inline_query":{"id":"1","from":{"id":1,"first_name":"Test"},"query":"cats","offset":""}}`,
+			wantType: UpdateTypeInlineQuery,
+			option: func(called *string) BotOption {
+				return OnInlineQuery(func(ctx context.Context, b *Bot, q *InlineQuery) error { *called = q.Query; return nil })
+			},
+		},
+		{
+			name:     "chat member",
+			raw:      `{"update_id":1,"chat_member":{"chat":{"id":-100,"type":"supergroup"},"from":{"id":1,"first_name":"Admin"},"date":1,"old_chat_member":{"status":"left","user":{"id":2,"first_name":"User"}},"new_chat_member":{"status":"member","user":{"id":2,"first_name":"User"}}}}`,
+			wantType: UpdateTypeChatMember,
+			option: func(called *string) BotOption {
+				return OnChatMember(func(ctx context.Context, b *Bot, u *ChatMemberUpdated) error {
+					*called = u.NewChatMember.Status
+					return nil
+				})
+			},
+		},
+		{
+			name:     "my chat member",
+			raw:      `{"update_id":1,"my_chat_member":{"chat":{"id":-100,"type":"group"},"from":{"id":1,"first_name":"Admin"},"date":1,"old_chat_member":{"status":"member","user":{"id":9,"first_name":"Bot"}},"new_chat_member":{"status":"kicked","user":{"id":9,"first_name":"Bot"},"until_date":0}}}`,
+			wantType: UpdateTypeMyChatMember,
+			option: func(called *string) BotOption {
+				return OnMyChatMember(func(ctx context.Context, b *Bot, u *ChatMemberUpdated) error {
+					*called = u.NewChatMember.Status
+					return nil
+				})
+			},
+		},
+		{
+			name:     "chat join request",
+			raw:      `{"update_id":1,"chat_join_request":{"chat":{"id":-100,"type":"supergroup"},"from":{"id":2,"first_name":"User"},"user_chat_id":2,"date":1,"bio":"hello"}}`,
+			wantType: UpdateTypeChatJoinRequest,
+			option: func(called *string) BotOption {
+				return OnChatJoinRequest(func(ctx context.Context, b *Bot, r *ChatJoinRequest) error { *called = r.Bio; return nil })
+			},
+		},
+		{
+			name:     "poll",
+			raw:      `{"update_id":1,"poll":{"id":"p1","question":"Lunch?","options":[{"text":"Yes","voter_count":1}],"total_voter_count":1,"is_closed":false,"is_anonymous":true,"type":"regular","allows_multiple_answers":false}}`,
+			wantType: UpdateTypePoll,
+			option: func(called *string) BotOption {
+				return OnPoll(func(ctx context.Context, b *Bot, p *Poll) error { *called = p.Question; return nil })
+			},
+		},
+		{
+			name:     "poll answer",
+			raw:      `{"update_id":1,"poll_answer":{"poll_id":"p1","user":{"id":2,"first_name":"User"},"option_ids":[0]}}`,
+			wantType: UpdateTypePollAnswer,
+			option: func(called *string) BotOption {
+				return OnPollAnswer(func(ctx context.Context, b *Bot, a *PollAnswer) error { *called = a.PollID; return nil })
+			},
+		},
+		{
+			name:     "message reaction",
+			raw:      `{"update_id":1,"message_reaction":{"chat":{"id":1,"type":"private"},"message_id":5,"user":{"id":1,"first_name":"Test"},"date":1,"old_reaction":[],"new_reaction":[{"type":"emoji","emoji":"👍"}]}}`,
+			wantType: UpdateTypeMessageReaction,
+			option: func(called *string) BotOption {
+				return OnMessageReaction(func(ctx context.Context, b *Bot, r *MessageReactionUpdated) error {
+					*called = r.NewReaction[0].Emoji
+					return nil
+				})
+			},
+		},
+		{
+			name:     "removed chat boost",
+			raw:      `{"update_id":1,"removed_chat_boost":{"chat":{"id":-100,"type":"channel"},"boost_id":"b1","remove_date":1,"source":{"source":"premium","user":{"id":1,"first_name":"Test"}}}}`,
+			wantType: UpdateTypeRemovedChatBoost,
+			option: func(called *string) BotOption {
+				return OnRemovedChatBoost(func(ctx context.Context, b *Bot, r *ChatBoostRemoved) error { *called = r.BoostID; return nil })
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update Update
+			if err := json.Unmarshal([]byte(tt.raw), &update); err != nil {
+				t.Fatalf("error unmarshaling update: %v", err)
+			}
+
+			if got := update.Type(); got != tt.wantType {
+				t.Errorf("expected type %q, got %q", tt.wantType, got)
+			}
+
+			var called string
+			bot := NewBot("test-token", WithLogger(testLogger()), tt.option(&called))
+			bot.handleUpdate(context.Background(), &update)
+
+			if called == "" {
+				t.Error("expected handler to be called")
+			}
+		})
+	}
+}
+
+func TestBot_handleUpdate_Fallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		update   *Update
+		fallback []string // Handlers de respaldo registrados
+		want     string   // Handler que debe atender el update
+	}{
+		{
+			name:     "text message",
+			update:   &Update{UpdateID: 1, Message: commandMessage("hola")},
+			fallback: []string{"message", "unknown", "update"},
+			want:     "message",
+		},
+		{
+			name:     "registered command",
+			update:   &Update{UpdateID: 1, Message: commandMessage("/start")},
+			fallback: []string{"message", "unknown", "update"},
+			want:     "command",
+		},
+		{
+			name:     "unknown command",
+			update:   &Update{UpdateID: 1, Message: commandMessage("/foo")},
+			fallback: []string{"message", "unknown", "update"},
+			want:     "unknown",
+		},
+		{
+			name:     "command for another bot",
+			update:   &Update{UpdateID: 1, Message: commandMessage("/foo@OtroBot")},
+			fallback: []string{"message", "unknown", "update"},
+			want:     "",
+		},
+		{
+			name:     "text message without message handler",
+			update:   &Update{UpdateID: 1, Message: commandMessage("hola")},
+			fallback: []string{"unknown", "update"},
+			want:     "update",
+		},
+		{
+			name:     "unknown command without unknown handler",
+			update:   &Update{UpdateID: 1, Message: commandMessage("/foo")},
+			fallback: []string{"message", "update"},
+			want:     "update",
+		},
+		{
+			name:     "update type without handler",
+			update:   &Update{UpdateID: 1, CallbackQuery: &CallbackQuery{ID: "1", From: &User{ID: 1}}},
+			fallback: []string{"message", "unknown", "update"},
+			want:     "update",
+		},
+		{
+			name:   "no fallback handlers",
+			update: &Update{UpdateID: 1, Message: commandMessage("hola")},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called []string
+			record := func(name string) func(context.Context, *Bot, *Message) error {
+				return func(context.Context, *Bot, *Message) error {
+					called = append(called, name)
+					return nil
+				}
+			}
+
+			registry := NewCommandRegistry()
+			registry.Handle("start", record("command"))
+
+			opts := []BotOption{WithLogger(testLogger()), WithCommandRegistry(registry)}
+			for _, fallback := range tt.fallback {
+				switch fallback {
+				case "message":
+					opts = append(opts, OnMessage(record("message")))
+				case "unknown":
+					opts = append(opts, OnUnknownCommand(record("unknown")))
+				case "update":
+					opts = append(opts, OnUpdate(func(context.Context, *Bot, *Update) error {
+						called = append(called, "update")
+						return nil
+					}))
+				}
+			}
+
+			bot := NewBot("test-token", opts...)
+			bot.me.Store(&User{ID: 1, IsBot: true, Username: "testbot"})
+
+			if tt.update.Message != nil {
+				tt.update.Message.From = &User{ID: 2, FirstName: "Test"}
+			}
+			bot.handleUpdate(context.Background(), tt.update)
+
+			var want []string
+			if tt.want != "" {
+				want = []string{tt.want}
+			}
+			if !reflect.DeepEqual(called, want) {
+				t.Errorf("expected handlers %v, got %v", want, called)
+			}
+		})
+	}
+}
+
+func TestUpdate_ChatAndFrom(t *testing.T) {
+	chat := &Chat{ID: -100, Type: "supergroup"}
+	user := &User{ID: 2, FirstName: "User"}
+
+	tests := []struct {
+		name     string
+		update   *Update
+		wantChat *Chat
+		wantFrom *User
+	}{
+		{name: "message", update: &Update{Message: &Message{Chat: chat, From: user}}, wantChat: chat, wantFrom: user},
+		{name: "edited channel post", update: &Update{EditedChannelPost: &Message{Chat: chat}}, wantChat: chat},
+		{name: "callback query", update: &Update{CallbackQuery: &CallbackQuery{From: user, Message: &Message{Chat: chat}}}, wantChat: chat, wantFrom: user},
+		{name: "inline callback query", update: &Update{CallbackQuery: &CallbackQuery{From: user, InlineMessageID: "1"}}, wantFrom: user},
+		{name: "inline query", update: &Update{InlineQuery: &InlineQuery{From: user}}, wantFrom: user},
+		{name: "chat member", update: &Update{ChatMember: &ChatMemberUpdated{Chat: chat, From: user}}, wantChat: chat, wantFrom: user},
+		{name: "poll answer", update: &Update{PollAnswer: &PollAnswer{User: user}}, wantFrom: user},
+		{name: "poll", update: &Update{Poll: &Poll{ID: "p1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.update.Chat(); got != tt.wantChat {
+				t.Errorf("expected chat %+v, got %+v", tt.wantChat, got)
+			}
+			if got := tt.update.From(); got != tt.wantFrom {
+				t.Errorf("expected from %+v, got %+v", tt.wantFrom, got)
+			}
+		})
+	}
+}
+
+func TestBot_handleUpdate_NoHandler(t *testing.T) {
+	update := &Update{
+		UpdateID:      1,
+		CallbackQuery: &CallbackQuery{ID: "1", From: &User{ID: 1}},
+	}
+
+	bot := NewBot("test-token", WithLogger(testLogger()))
+
+	// No debe entrar en pánico si no hay handler registrado
+	bot.handleUpdate(context.Background(), update)
+}
+
+func TestUpdate_Type_Empty(t *testing.T) {
+	update := &Update{UpdateID: 1}
+	if got := update.Type(); got != "" {
+		t.Errorf("expected empty type, got %q", got)
+	}
+}
+
+func TestBot_getUpdates_AllowedUpdates(t *testing.T) {
+	var params map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&params)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":[]}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:          "test-token",
+		client:         &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL:     server.URL + "/bot%s/%s",
+		logger:         testLogger(),
+		allowedUpdates: []string{UpdateTypeMessage, UpdateTypeChatMember},
+	}
+
+	if _, err := bot.getUpdates(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := string(params["allowed_updates"]); got != `["message","chat_member"]` {
+		t.Errorf("expected allowed_updates to be sent, got %s", got)
+	}
+}
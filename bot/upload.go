@@ -0,0 +1,243 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"mime/multipart"
+	"slices"
+)
+
+// InputFile representa un archivo a enviar. Puede ser un file_id de un archivo
+// que ya está en los servidores de Telegram, una URL que Telegram descargará,
+// o contenido nuevo leído de un io.Reader.
+type InputFile struct {
+	fileID   string
+	url      string
+	filename string
+	reader   io.Reader
+}
+
+// FileID referencia un archivo ya almacenado en Telegram. Es la forma más
+// eficiente de reenviar un archivo, ya que no se vuelve a subir.
+func FileID(id string) InputFile {
+	return InputFile{fileID: id}
+}
+
+// FileURL indica una URL HTTP desde la que Telegram descargará el archivo.
+func FileURL(url string) InputFile {
+	return InputFile{url: url}
+}
+
+// FileReader sube el contenido leído de r con el nombre de archivo indicado.
+// El contenido se copia directamente al body del request, sin cargarlo
+// completo en memoria. El reader solo se lee una vez, por lo que los envíos
+// que suben archivos no se reintentan.
+//
+// Ejemplo:
+//
+//	f, _ := os.Open("reporte.csv")
+//	defer f.Close()
+//	msg, err := b.SendDocument(ctx, chatID, bot.FileReader("reporte.csv", f), "Reporte diario")
+func FileReader(filename string, r io.Reader) InputFile {
+	return InputFile{filename: filename, reader: r}
+}
+
+// MarshalJSON codifica el file_id o la URL. Los archivos a subir se envían
+// como partes del multipart, por lo que se codifican como null.
+func (f InputFile) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.reader != nil:
+		return []byte("null"), nil
+	case f.fileID != "":
+		return json.Marshal(f.fileID)
+	default:
+		return json.Marshal(f.url)
+	}
+}
+
+// fileUploader es implementado por los payloads que pueden incluir archivos,
+// indexados por el nombre del parámetro de la API.
+type fileUploader interface {
+	files() map[string]InputFile
+}
+
+func (r SendPhotoRequest) files() map[string]InputFile {
+	return map[string]InputFile{"photo": r.Photo}
+}
+
+func (r SendDocumentRequest) files() map[string]InputFile {
+	return map[string]InputFile{"document": r.Document}
+}
+
+func (r SendAudioRequest) files() map[string]InputFile {
+	return map[string]InputFile{"audio": r.Audio}
+}
+
+func (r SendVideoRequest) files() map[string]InputFile {
+	return map[string]InputFile{"video": r.Video}
+}
+
+func (r SendVoiceRequest) files() map[string]InputFile {
+	return map[string]InputFile{"voice": r.Voice}
+}
+
+func (r SendAnimationRequest) files() map[string]InputFile {
+	return map[string]InputFile{"animation": r.Animation}
+}
+
+// pendingUploads retorna los archivos del payload que deben subirse.
+func pendingUploads(payload any) map[string]InputFile {
+	uploader, ok := payload.(fileUploader)
+	if !ok {
+		return nil
+	}
+
+	pending := make(map[string]InputFile)
+	for field, file := range uploader.files() {
+		if file.reader != nil {
+			pending[field] = file
+		}
+	}
+	return pending
+}
+
+// uploadRequest envía el payload como multipart/form-data. El body se escribe
+// en un pipe a medida que el cliente HTTP lo consume, así los archivos no se
+// cargan en memoria.
+func (b *Bot) uploadRequest(ctx context.Context, method string, payload any, files map[string]InputFile) (*Response, error) {
+	fields, err := multipartFields(payload)
+	if err != nil {
+		b.logger.Error("Error marshaling payload",
+			slog.String("method", method),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("error marshaling payload: %w", err)
+	}
+
+	if err := b.waitRateLimit(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		pw.CloseWithError(writeMultipart(writer, fields, files))
+	}()
+
+	resp, err := b.doRequest(ctx, method, pr, writer.FormDataContentType())
+
+	// Si el request terminó sin consumir todo el body, cerrar el pipe corta la
+	// escritura. Se espera a que termine para no seguir leyendo los archivos
+	// después de retornar, cuando el llamador ya puede estar cerrándolos.
+	pr.Close()
+	<-written
+
+	return resp, err
+}
+
+// multipartFields convierte el payload en los campos de texto del formulario.
+// Los valores que no son strings, como reply_markup, se envían serializados
+// en JSON, que es lo que la API espera.
+func multipartFields(payload any) (map[string]string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if string(value) == "null" {
+			continue
+		}
+
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			fields[key] = s
+			continue
+		}
+		fields[key] = string(value)
+	}
+
+	return fields, nil
+}
+
+func writeMultipart(writer *multipart.Writer, fields map[string]string, files map[string]InputFile) error {
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if err := writer.WriteField(key, fields[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(files)) {
+		file := files[field]
+		part, err := writer.CreateFormFile(field, file.filename)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.reader); err != nil {
+			return fmt.Errorf("error reading file %s: %w", file.filename, err)
+		}
+	}
+
+	return writer.Close()
+}
+
+// SendPhoto envía una foto con un caption opcional.
+func (b *Bot) SendPhoto(ctx context.Context, chatID int64, photo InputFile, caption string, opts ...SendOption) (*Message, error) {
+	payload := SendPhotoRequest{ChatID: chatID, Photo: photo, Caption: caption}
+	payload.apply(opts)
+
+	return b.sendRequest(ctx, "sendPhoto", payload)
+}
+
+// SendDocument envía un archivo genérico, como un CSV o un PDF.
+func (b *Bot) SendDocument(ctx context.Context, chatID int64, document InputFile, caption string, opts ...SendOption) (*Message, error) {
+	payload := SendDocumentRequest{ChatID: chatID, Document: document, Caption: caption}
+	payload.apply(opts)
+
+	return b.sendRequest(ctx, "sendDocument", payload)
+}
+
+// SendAudio envía un archivo de audio para mostrarse en el reproductor de
+// música. Para mensajes de voz usar SendVoice.
+func (b *Bot) SendAudio(ctx context.Context, chatID int64, audio InputFile, caption string, opts ...SendOption) (*Message, error) {
+	payload := SendAudioRequest{ChatID: chatID, Audio: audio, Caption: caption}
+	payload.apply(opts)
+
+	return b.sendRequest(ctx, "sendAudio", payload)
+}
+
+// SendVideo envía un video en formato MPEG4.
+func (b *Bot) SendVideo(ctx context.Context, chatID int64, video InputFile, caption string, opts ...SendOption) (*Message, error) {
+	payload := SendVideoRequest{ChatID: chatID, Video: video, Caption: caption}
+	payload.apply(opts)
+
+	return b.sendRequest(ctx, "sendVideo", payload)
+}
+
+// SendVoice envía un mensaje de voz (OGG con OPUS, MP3 o M4A).
+func (b *Bot) SendVoice(ctx context.Context, chatID int64, voice InputFile, caption string, opts ...SendOption) (*Message, error) {
+	payload := SendVoiceRequest{ChatID: chatID, Voice: voice, Caption: caption}
+	payload.apply(opts)
+
+	return b.sendRequest(ctx, "sendVoice", payload)
+}
+
+// SendAnimation envía una animación (GIF o video H.264/MPEG-4 sin sonido).
+func (b *Bot) SendAnimation(ctx context.Context, chatID int64, animation InputFile, caption string, opts ...SendOption) (*Message, error) {
+	payload := SendAnimationRequest{ChatID: chatID, Animation: animation, Caption: caption}
+	payload.apply(opts)
+
+	return b.sendRequest(ctx, "sendAnimation", payload)
+}
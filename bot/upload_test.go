@@ -0,0 +1,293 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBot_SendDocument_Upload(t *testing.T) {
+	var (
+		fields   map[string]string
+		filename string
+		content  string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			t.Errorf("expected sendDocument, got %s", r.URL.Path)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("expected multipart body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fields = make(map[string]string)
+		for key, values := range r.MultipartForm.Value {
+			fields[key] = values[0]
+		}
+
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("expected document file part: %v", err)
+		} else {
+			defer file.Close()
+			data, _ := io.ReadAll(file)
+			filename = header.Filename
+			content = string(data)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":7,"chat":{"id":123,"type":"private"},"date":1}}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	report := strings.NewReader("fecha,total\n2024-01-01,42\n")
+	msg, err := bot.SendDocument(context.Background(), 123, FileReader("reporte.csv", report), "<b>Reporte</b>",
+		WithParseMode(ParseModeHTML),
+		WithReplyMarkup(NewInlineKeyboard().Row(CallbackButton("OK", "ok")).Build()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if msg.MessageID != 7 {
+		t.Errorf("expected MessageID 7, got %d", msg.MessageID)
+	}
+
+	want := map[string]string{
+		"chat_id":      "123",
+		"caption":      "<b>Reporte</b>",
+		"parse_mode":   "HTML",
+		"reply_markup": `{"inline_keyboard":[[{"text":"OK","callback_data":"ok"}]]}`,
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("expected field %s=%q, got %q", key, value, fields[key])
+		}
+	}
+
+	if _, ok := fields["document"]; ok {
+		t.Error("expected document to be sent only as a file part")
+	}
+
+	if filename != "reporte.csv" {
+		t.Errorf("expected filename reporte.csv, got %q", filename)
+	}
+
+	if content != "fecha,total\n2024-01-01,42\n" {
+		t.Errorf("unexpected file content %q", content)
+	}
+}
+
+func TestBot_SendFile_Reference(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		field  string
+		file   InputFile
+		want   string
+		send   func(b *Bot, file InputFile) (*Message, error)
+	}{
+		{
+			name:   "photo by file_id",
+			method: "sendPhoto",
+			field:  "photo",
+			file:   FileID("AgACAgIAAxkBAAIB"),
+			want:   `"AgACAgIAAxkBAAIB"`,
+			send: func(b *Bot, file InputFile) (*Message, error) {
+				return b.SendPhoto(context.Background(), 123, file, "")
+			},
+		},
+		{
+			name:   "audio by url",
+			method: "sendAudio",
+			field:  "audio",
+			file:   FileURL("https://example.com/song.mp3"),
+			want:   `"https://example.com/song.mp3"`,
+			send: func(b *Bot, file InputFile) (*Message, error) {
+				return b.SendAudio(context.Background(), 123, file, "")
+			},
+		},
+		{
+			name:   "video by file_id",
+			method: "sendVideo",
+			field:  "video",
+			file:   FileID("BAACAgIAAxkBAAIC"),
+			want:   `"BAACAgIAAxkBAAIC"`,
+			send: func(b *Bot, file InputFile) (*Message, error) {
+				return b.SendVideo(context.Background(), 123, file, "")
+			},
+		},
+		{
+			name:   "voice by file_id",
+			method: "sendVoice",
+			field:  "voice",
+			file:   FileID("AwACAgIAAxkBAAID"),
+			want:   `"AwACAgIAAxkBAAID"`,
+			send: func(b *Bot, file InputFile) (*Message, error) {
+				return b.SendVoice(context.Background(), 123, file, "")
+			},
+		},
+		{
+			name:   "animation by url",
+			method: "sendAnimation",
+			field:  "animation",
+			file:   FileURL("https://example.com/cat.gif"),
+			want:   `"https://example.com/cat.gif"`,
+			send: func(b *Bot, file InputFile) (*Message, error) {
+				return b.SendAnimation(context.Background(), 123, file, "")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received map[string]json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/"+tt.method) {
+					t.Errorf("expected method %s, got %s", tt.method, r.URL.Path)
+				}
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("expected JSON body, got %s", ct)
+				}
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":123,"type":"private"},"date":1}}`))
+			}))
+			defer server.Close()
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			if _, err := tt.send(bot, tt.file); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := string(received[tt.field]); got != tt.want {
+				t.Errorf("expected %s=%s, got %s", tt.field, tt.want, got)
+			}
+		})
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk error")
+}
+
+func TestBot_SendPhoto_Upload_NotRetried(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader io.Reader
+		status int
+		body   string
+	}{
+		{
+			name:   "server error",
+			reader: strings.NewReader("image"),
+			status: http.StatusInternalServerError,
+			body:   `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+		},
+		{
+			name:   "reader error",
+			reader: failingReader{},
+			status: http.StatusOK,
+			body:   `{"ok":true,"result":{"message_id":1}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := DefaultRetryPolicy()
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				// Consumir el body como lo haría la API antes de responder
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					return
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			bot := &Bot{
+				token:       "test-token",
+				client:      &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL:  server.URL + "/bot%s/%s",
+				logger:      testLogger(),
+				retryPolicy: &policy,
+				clock:       newFakeClock(),
+			}
+
+			_, err := bot.SendPhoto(context.Background(), 123, FileReader("photo.jpg", tt.reader), "")
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if got := requests.Load(); got > 1 {
+				t.Errorf("expected upload not to be retried, got %d requests", got)
+			}
+		})
+	}
+}
+
+// endlessReader entrega datos sin terminar, con una demora en cada lectura,
+// y cuenta las lecturas en curso.
+type endlessReader struct {
+	reading atomic.Int32
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	r.reading.Add(1)
+	defer r.reading.Add(-1)
+
+	time.Sleep(5 * time.Millisecond)
+	return len(p), nil
+}
+
+func TestBot_SendPhoto_Upload_StopsReadingOnReturn(t *testing.T) {
+	// La API responde sin leer el body, como ante un token inválido, mientras
+	// el archivo todavía se está subiendo
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	bot := NewBot("test-token", WithLogger(testLogger()))
+	bot.apiBaseURL = server.URL + "/bot%s/%s"
+
+	for range 5 {
+		reader := &endlessReader{}
+		if _, err := bot.SendPhoto(context.Background(), 123, FileReader("photo.jpg", reader), ""); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		// Al retornar, el llamador puede cerrar el reader
+		if n := reader.reading.Load(); n > 0 {
+			t.Fatalf("expected the file not to be read after SendPhoto returned, got %d reads in progress", n)
+		}
+	}
+}
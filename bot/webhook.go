@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// webhookSecretHeader es el header que Telegram incluye en cada request
+	// del webhook cuando se configuró un secret_token en setWebhook.
+	webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// maxWebhookBodySize limita el tamaño del payload aceptado por el webhook.
+	maxWebhookBodySize = 1 << 20
+)
+
+// WithWebhookSecretToken configura el token secreto que Telegram debe enviar
+// en el header X-Telegram-Bot-Api-Secret-Token de cada request del webhook.
+// Los requests que no lo incluyan son rechazados con 401.
+//
+// El mismo token se envía automáticamente en SetWebhook si el request no
+// especifica uno.
+//
+// Ejemplo:
+//
+//	bot := bot.NewBot(token, bot.WithWebhookSecretToken(os.Getenv("WEBHOOK_SECRET")))
+func WithWebhookSecretToken(secret string) BotOption {
+	return func(b *Bot) {
+		b.webhookSecret = secret
+	}
+}
+
+// WebhookHandler retorna un http.Handler que recibe las actualizaciones
+// enviadas por Telegram y las procesa con los mismos handlers que Start.
+// Es una alternativa a Start: un bot con webhook activo no puede usar
+// long polling.
+//
+// Cada update se procesa de forma sincrónica dentro del request, por lo que
+// Telegram no enviará más actualizaciones por esa conexión hasta que el
+// handler termine.
+//
+// Ejemplo:
+//
+//	http.Handle("/telegram", bot.WebhookHandler())
+//	log.Fatal(http.ListenAndServe(":8080", nil))
+func (b *Bot) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !b.validWebhookSecret(r.Header.Get(webhookSecretHeader)) {
+			b.logger.Warn("Request de webhook con secret token inválido",
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var raw json.RawMessage
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodySize)).Decode(&raw)
+		var update *Update
+		if err == nil {
+			update, err = decodeUpdate(raw)
+		}
+		if err != nil {
+			b.logger.Error("Error decodificando update del webhook",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		b.handleUpdate(r.Context(), update)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// validWebhookSecret compara el token recibido con el configurado en tiempo
+// constante. Si no hay token configurado se acepta cualquier request.
+func (b *Bot) validWebhookSecret(received string) bool {
+	if b.webhookSecret == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(received), []byte(b.webhookSecret)) == 1
+}
+
+// SetWebhook registra la URL a la que Telegram enviará las actualizaciones.
+// Si el request no define SecretToken se usa el configurado con
+// WithWebhookSecretToken.
+func (b *Bot) SetWebhook(ctx context.Context, req SetWebhookRequest) error {
+	if req.SecretToken == "" {
+		req.SecretToken = b.webhookSecret
+	}
+
+	_, err := b.makeRequest(ctx, "setWebhook", req)
+	return err
+}
+
+// DeleteWebhook elimina el webhook configurado para volver a usar long polling.
+// Si dropPendingUpdates es true, Telegram descarta las actualizaciones pendientes.
+func (b *Bot) DeleteWebhook(ctx context.Context, dropPendingUpdates bool) error {
+	payload := DeleteWebhookRequest{
+		DropPendingUpdates: dropPendingUpdates,
+	}
+
+	_, err := b.makeRequest(ctx, "deleteWebhook", payload)
+	return err
+}
+
+// GetWebhookInfo obtiene el estado actual del webhook del bot.
+func (b *Bot) GetWebhookInfo(ctx context.Context) (*WebhookInfo, error) {
+	resp, err := b.makeRequest(ctx, "getWebhookInfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info WebhookInfo
+	if err := json.Unmarshal(resp.Result, &info); err != nil {
+		return nil, fmt.Errorf("error unmarshaling webhook info: %w", err)
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,231 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBot_WebhookHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		secret      string
+		header      string
+		body        string
+		wantStatus  int
+		wantCommand bool
+	}{
+		{
+			name:        "valid update without secret",
+			method:      http.MethodPost,
+			body:        `{"update_id":1,"message":{"message_id":1,"from":{"id":1,"first_name":"Test"},"chat":{"id":123,"type":"private"},"text":"/start","entities":[{"type":"bot_command","offset":0,"length":6}]}}`,
+			wantStatus:  http.StatusOK,
+			wantCommand: true,
+		},
+		{
+			name:        "valid update with secret",
+			method:      http.MethodPost,
+			secret:      "s3cr3t",
+			header:      "s3cr3t",
+			body:        `{"update_id":1,"message":{"message_id":1,"from":{"id":1,"first_name":"Test"},"chat":{"id":123,"type":"private"},"text":"/start","entities":[{"type":"bot_command","offset":0,"length":6}]}}`,
+			wantStatus:  http.StatusOK,
+			wantCommand: true,
+		},
+		{
+			name:       "wrong secret",
+			method:     http.MethodPost,
+			secret:     "s3cr3t",
+			header:     "other",
+			body:       `{"update_id":1,"message":{"message_id":1,"from":{"id":1,"first_name":"Test"},"chat":{"id":123,"type":"private"},"text":"/start","entities":[{"type":"bot_command","offset":0,"length":6}]}}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing secret",
+			method:     http.MethodPost,
+			secret:     "s3cr3t",
+			body:       `{"update_id":1}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid JSON",
+			method:     http.MethodPost,
+			body:       `invalid json`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			registry := NewCommandRegistry()
+			registry.Register("start", func(ctx context.Context, b *Bot, msg *Message) {
+				called = true
+			})
+
+			bot := NewBot("test-token",
+				WithLogger(testLogger()),
+				WithCommandRegistry(registry),
+				WithWebhookSecretToken(tt.secret),
+			)
+
+			req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(webhookSecretHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			bot.WebhookHandler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if called != tt.wantCommand {
+				t.Errorf("expected command called=%v, got %v", tt.wantCommand, called)
+			}
+		})
+	}
+}
+
+func TestBot_SetWebhook(t *testing.T) {
+	var received SetWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/setWebhook") {
+			t.Errorf("expected setWebhook method, got %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:         "test-token",
+		client:        &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL:    server.URL + "/bot%s/%s",
+		logger:        testLogger(),
+		webhookSecret: "s3cr3t",
+	}
+
+	err := bot.SetWebhook(context.Background(), SetWebhookRequest{
+		URL:            "https://example.com/webhook",
+		AllowedUpdates: []string{"message"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.URL != "https://example.com/webhook" {
+		t.Errorf("expected URL to be sent, got %q", received.URL)
+	}
+
+	if received.SecretToken != "s3cr3t" {
+		t.Errorf("expected configured secret token, got %q", received.SecretToken)
+	}
+
+	if len(received.AllowedUpdates) != 1 || received.AllowedUpdates[0] != "message" {
+		t.Errorf("expected allowed updates [message], got %v", received.AllowedUpdates)
+	}
+}
+
+func TestBot_DeleteWebhook(t *testing.T) {
+	var received DeleteWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/deleteWebhook") {
+			t.Errorf("expected deleteWebhook method, got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		token:      "test-token",
+		client:     &http.Client{Timeout: 5 * time.Second},
+		apiBaseURL: server.URL + "/bot%s/%s",
+		logger:     testLogger(),
+	}
+
+	if err := bot.DeleteWebhook(context.Background(), true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !received.DropPendingUpdates {
+		t.Error("expected drop_pending_updates to be true")
+	}
+}
+
+func TestBot_GetWebhookInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantURL  string
+		wantErr  bool
+	}{
+		{
+			name:     "webhook configured",
+			response: `{"ok":true,"result":{"url":"https://example.com/webhook","has_custom_certificate":false,"pending_update_count":3}}`,
+			wantURL:  "https://example.com/webhook",
+		},
+		{
+			name:     "API error",
+			response: `{"ok":false,"description":"Unauthorized"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid result",
+			response: `{"ok":true,"result":"invalid"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			bot := &Bot{
+				token:      "test-token",
+				client:     &http.Client{Timeout: 5 * time.Second},
+				apiBaseURL: server.URL + "/bot%s/%s",
+				logger:     testLogger(),
+			}
+
+			info, err := bot.GetWebhookInfo(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if info.URL != tt.wantURL {
+				t.Errorf("expected URL %q, got %q", tt.wantURL, info.URL)
+			}
+
+			if info.PendingUpdateCount != 3 {
+				t.Errorf("expected 3 pending updates, got %d", info.PendingUpdateCount)
+			}
+		})
+	}
+}
@@ -12,7 +12,7 @@ import (
 
 func commandStart(ctx context.Context, b *bot.Bot, msg *bot.Message) {
 	welcome := "¡Hola! Soy un bot de Telegram."
-	if err := b.SendMessage(ctx, msg.Chat.ID, welcome); err != nil {
+	if _, err := b.SendMessage(ctx, msg.Chat.ID, welcome); err != nil {
 		log.Printf("Error enviando bienvenida: %v", err)
 	}
 }